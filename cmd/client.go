@@ -0,0 +1,338 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"text/tabwriter"
+
+	"communications/internal/config"
+	"communications/internal/database"
+	"communications/internal/database/models"
+	"communications/internal/repository"
+	"communications/internal/services"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Manages clients: add, list, check <id>, verify <id>, resend <id> and delete <id>.
+func clientCommand(cfg *config.Config, args []string) error {
+	if len(args) == 0 {
+		return errors.New("usage: client add|list|check <id>|verify [--channel email|sms|both] <id>|resend <id>|delete <id>")
+	}
+
+	// Adding a client and resending send signed verification links and codes.
+	if args[0] == "add" || args[0] == "resend" {
+		if err := cfg.RequireVerificationSecret(); err != nil {
+			return err
+		}
+	}
+
+	switch args[0] {
+	case "add":
+		return addClient(cfg, args[1:])
+	case "list":
+		return listClients(cfg)
+	case "check":
+		return checkClient(cfg, args[1:])
+	case "verify":
+		return verifyClient(cfg, args[1:])
+	case "resend":
+		return sendVerification(cfg, args[1:])
+	case "delete":
+		return deleteClient(cfg, args[1:])
+	default:
+		return fmt.Errorf("unknown client command %q", args[0])
+	}
+}
+
+// Adds a client from flags, prints its generated ID and sends the verification link and code to its contacts.
+// Failing to send a verification is reported but keeps the client, so it can be resent with client resend.
+func addClient(cfg *config.Config, args []string) error {
+	var client models.Client
+
+	flags := flag.NewFlagSet("client add", flag.ContinueOnError)
+	flags.StringVar(&client.Name, "name", "", "name of the client (required)")
+	flags.StringVar(&client.Email, "email", "", "email receiving lead notifications (required)")
+	flags.StringVar(&client.Phone, "phone", "", "phone receiving lead notifications (required)")
+	flags.Func("country", "ISO 3166-1 alpha-2 country used to normalize national phone numbers", optionalString(&client.Country))
+	flags.Func("website", "website URL", optionalString(&client.Website))
+	flags.Func("email-quota", "monthly email quota overriding EMAIL_QUOTA", optionalInt(&client.EmailQuota))
+	flags.Func("sms-quota", "monthly SMS quota overriding SMS_QUOTA", optionalInt(&client.SMSQuota))
+
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if client.Name == "" || client.Email == "" || client.Phone == "" {
+		return errors.New("--name, --email and --phone are required")
+	}
+
+	service, db, err := connect(cfg)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	if err := service.CreateClient(context.Background(), &client); err != nil {
+		return fmt.Errorf("unable to add the client: %w", err)
+	}
+
+	fmt.Printf("Client %q added with ID %s (email %s, phone %s).\n", client.Name, client.ID, client.Email, client.Phone)
+
+	return reportVerification(service.SendVerification(context.Background(), &client))
+}
+
+// Prints all clients as a table.
+func listClients(cfg *config.Config) error {
+	service, db, err := connect(cfg)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	clients, err := service.Clients.ListClients(context.Background())
+	if err != nil {
+		return fmt.Errorf("unable to list the clients: %w", err)
+	}
+
+	table := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(table, "ID\tNAME\tEMAIL\tPHONE\tCOUNTRY\tVERIFIED\tCREATED")
+
+	for _, client := range clients {
+		fmt.Fprintf(table, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			client.ID, client.Name, client.Email, client.Phone, value(client.Country), verification(client), client.CreatedAt.Format("2006-01-02"))
+	}
+
+	return table.Flush()
+}
+
+// Checks if the client's email and phone can receive notifications, without changing the client.
+// Fails when either contact looks undeliverable, so the command can be used in scripts.
+func checkClient(cfg *config.Config, args []string) error {
+	id, err := parseClientID(args)
+	if err != nil {
+		return err
+	}
+
+	service, db, err := connect(cfg)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	ctx := context.Background()
+
+	client, err := service.Clients.FindClient(ctx, id)
+	if err != nil {
+		return fmt.Errorf("unable to find client %s: %w", id, err)
+	}
+
+	check := service.CheckClient(ctx, &client)
+
+	phone := "valid"
+	if !check.Phone {
+		phone = "invalid"
+	}
+
+	fmt.Printf("Email %s: %s\nPhone %s: %s\n", client.Email, check.Email, client.Phone, phone)
+
+	if check.Email == services.EmailVerdictUndeliverable || check.Email == services.EmailVerdictDisposable || !check.Phone {
+		return fmt.Errorf("client %s cannot receive all notifications", client.ID)
+	}
+
+	return nil
+}
+
+// Marks the client's email, phone or both as verified, e.g. for clients onboarded manually,
+// so leads reach them without the verification link and code.
+func verifyClient(cfg *config.Config, args []string) error {
+	flags := flag.NewFlagSet("client verify", flag.ContinueOnError)
+	channel := flags.String("channel", "both", "contact to mark as verified: email, sms or both")
+
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if *channel != "email" && *channel != "sms" && *channel != "both" {
+		return fmt.Errorf("unknown channel %q (use email, sms or both)", *channel)
+	}
+
+	id, err := parseClientID(flags.Args())
+	if err != nil {
+		return err
+	}
+
+	service, db, err := connect(cfg)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	ctx := context.Background()
+
+	client, err := service.Clients.FindClient(ctx, id)
+	if err != nil {
+		return fmt.Errorf("unable to find client %s: %w", id, err)
+	}
+
+	if *channel != "sms" {
+		if err := service.Clients.VerifyEmail(ctx, id, client.Email); err != nil {
+			return fmt.Errorf("unable to verify the email: %w", err)
+		}
+	}
+
+	if *channel != "email" {
+		if err := service.Clients.VerifyPhone(ctx, id, client.Phone); err != nil {
+			return fmt.Errorf("unable to verify the phone: %w", err)
+		}
+	}
+
+	client, err = service.Clients.FindClient(ctx, id)
+	if err != nil {
+		return fmt.Errorf("unable to find client %s: %w", id, err)
+	}
+
+	fmt.Printf("Client %s verified: %s\n", id, verification(client))
+
+	return nil
+}
+
+// Resends the verification link and code to the client's unverified contacts.
+// Sending a new code invalidates the previous one.
+func sendVerification(cfg *config.Config, args []string) error {
+	id, err := parseClientID(args)
+	if err != nil {
+		return err
+	}
+
+	service, db, err := connect(cfg)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	ctx := context.Background()
+
+	client, err := service.Clients.FindClient(ctx, id)
+	if err != nil {
+		return fmt.Errorf("unable to find client %s: %w", id, err)
+	}
+	if client.EmailVerified && client.PhoneVerified {
+		fmt.Printf("Client %s is already verified.\n", id)
+		return nil
+	}
+
+	return reportVerification(service.SendVerification(ctx, &client))
+}
+
+// Prints which verification messages were sent and fails if any of them could not be sent.
+func reportVerification(emailError, smsError error) error {
+	if emailError == nil {
+		fmt.Println("Email verification: sent (or already verified)")
+	} else {
+		fmt.Printf("Email verification: %v\n", emailError)
+	}
+
+	if smsError == nil {
+		fmt.Println("Phone verification: sent (or already verified)")
+	} else {
+		fmt.Printf("Phone verification: %v\n", smsError)
+	}
+
+	if emailError != nil || smsError != nil {
+		return errors.New("unable to send every verification, retry with client resend")
+	}
+
+	return nil
+}
+
+// Describes which of the client's contacts are verified.
+func verification(client models.Client) string {
+	switch {
+	case client.EmailVerified && client.PhoneVerified:
+		return "yes"
+	case client.EmailVerified:
+		return "email"
+	case client.PhoneVerified:
+		return "phone"
+	default:
+		return "no"
+	}
+}
+
+// Soft-deletes the client, so it stops receiving leads.
+func deleteClient(cfg *config.Config, args []string) error {
+	id, err := parseClientID(args)
+	if err != nil {
+		return err
+	}
+
+	service, db, err := connect(cfg)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	if err := service.Clients.DeleteClient(context.Background(), id); err != nil {
+		return fmt.Errorf("unable to delete the client: %w", err)
+	}
+
+	fmt.Printf("Client %s deleted.\n", id)
+
+	return nil
+}
+
+// Connects to the database and creates a service for a management command.
+// The caller must close the returned pool.
+func connect(cfg *config.Config) (*services.Service, *pgxpool.Pool, error) {
+	db, err := database.Connect(cfg)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return services.NewService(repository.NewPostgres(db), cfg), db, nil
+}
+
+// Returns the client ID given as the only argument.
+func parseClientID(args []string) (string, error) {
+	if len(args) != 1 {
+		return "", errors.New("a single client ID is required")
+	}
+	if err := uuid.Validate(args[0]); err != nil {
+		return "", fmt.Errorf("invalid client ID %q", args[0])
+	}
+
+	return args[0], nil
+}
+
+// Returns a flag setter storing a string in an optional field.
+func optionalString(field **string) func(string) error {
+	return func(text string) error {
+		*field = &text
+		return nil
+	}
+}
+
+// Returns a flag setter storing a non-negative integer in an optional field.
+func optionalInt(field **int) func(string) error {
+	return func(text string) error {
+		number, err := strconv.Atoi(text)
+		if err != nil || number < 0 {
+			return fmt.Errorf("%q is not a non-negative number", text)
+		}
+		*field = &number
+		return nil
+	}
+}
+
+// Returns the value of an optional field, or an empty string if it is not set.
+func value(field *string) string {
+	if field == nil {
+		return ""
+	}
+
+	return *field
+}
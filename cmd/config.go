@@ -0,0 +1,26 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"communications/internal/config"
+)
+
+// Checks or prints the configuration. Invalid configurations never get here, because loading fails first.
+func configCommand(cfg *config.Config, args []string) error {
+	if len(args) != 1 {
+		return errors.New("usage: config check|print")
+	}
+
+	switch args[0] {
+	case "check":
+		fmt.Println("Configuration is valid.")
+		return nil
+	case "print":
+		return cfg.Print(os.Stdout)
+	default:
+		return fmt.Errorf("unknown config command %q", args[0])
+	}
+}
@@ -0,0 +1,134 @@
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"communications/internal/config"
+	"communications/internal/database/models"
+	"communications/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+// Columns of the CSV lead export, in order.
+var leadColumns = []string{
+	"id", "datetime", "client_id", "name", "email", "phone", "email_verdict",
+	"utm_source", "utm_medium", "utm_campaign", "utm_term", "utm_content",
+	"referrer", "landing_page", "user_agent", "ip_address", "country", "city",
+}
+
+// Manages leads: export.
+func leadCommand(cfg *config.Config, args []string) error {
+	if len(args) == 0 || args[0] != "export" {
+		return errors.New("usage: lead export [--client <id>] [--from YYYY-MM-DD] [--to YYYY-MM-DD] [--format csv|json]")
+	}
+
+	var filter repository.LeadFilter
+	var from, to string
+
+	flags := flag.NewFlagSet("lead export", flag.ContinueOnError)
+	flags.StringVar(&filter.ClientID, "client", "", "only export leads of this client ID")
+	flags.StringVar(&from, "from", "", "only export leads submitted on or after this date (YYYY-MM-DD)")
+	flags.StringVar(&to, "to", "", "only export leads submitted on or before this date (YYYY-MM-DD)")
+	format := flags.String("format", "csv", "output format: csv or json (one JSON object per line)")
+
+	if err := flags.Parse(args[1:]); err != nil {
+		return err
+	}
+
+	if filter.ClientID != "" && uuid.Validate(filter.ClientID) != nil {
+		return fmt.Errorf("invalid client ID %q", filter.ClientID)
+	}
+
+	var err error
+	if filter.From, err = parseDate(from); err != nil {
+		return fmt.Errorf("--from: %w", err)
+	}
+	if filter.To, err = parseDate(to); err != nil {
+		return fmt.Errorf("--to: %w", err)
+	}
+	if !filter.To.IsZero() {
+		filter.To = filter.To.AddDate(0, 0, 1)
+	}
+
+	var write func(models.Lead) error
+	var flush func() error
+
+	switch *format {
+	case "csv":
+		writer := csv.NewWriter(os.Stdout)
+		if err := writer.Write(leadColumns); err != nil {
+			return err
+		}
+		write = func(lead models.Lead) error { return writer.Write(leadRecord(lead)) }
+		flush = func() error { writer.Flush(); return writer.Error() }
+	case "json":
+		encoder := json.NewEncoder(os.Stdout)
+		write = func(lead models.Lead) error { return encoder.Encode(lead) }
+		flush = func() error { return nil }
+	default:
+		return fmt.Errorf("unknown format %q (use csv or json)", *format)
+	}
+
+	service, db, err := connect(cfg)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	if err := service.Leads.ExportLeads(context.Background(), filter, write); err != nil {
+		return fmt.Errorf("unable to export the leads: %w", err)
+	}
+
+	return flush()
+}
+
+// Parses an optional YYYY-MM-DD date; an empty value returns the zero time.
+func parseDate(text string) (time.Time, error) {
+	if text == "" {
+		return time.Time{}, nil
+	}
+
+	date, err := time.Parse(time.DateOnly, text)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%q is not a YYYY-MM-DD date", text)
+	}
+
+	return date, nil
+}
+
+// Converts a lead into a CSV record matching leadColumns.
+// Cells are escaped with csvCell, since most of the values were submitted by the public.
+func leadRecord(lead models.Lead) []string {
+	record := []string{
+		strconv.Itoa(lead.ID), lead.Datetime.Format(time.RFC3339), lead.ClientID,
+		lead.Name, lead.Email, lead.Phone, value(lead.EmailVerdict),
+		value(lead.UTMSource), value(lead.UTMMedium), value(lead.UTMCampaign), value(lead.UTMTerm), value(lead.UTMContent),
+		value(lead.Referrer), value(lead.LandingPage), value(lead.UserAgent), value(lead.IPAddress), value(lead.Country), value(lead.City),
+	}
+
+	for i := range record {
+		record[i] = csvCell(record[i])
+	}
+
+	return record
+}
+
+// Prefixes a cell starting with a formula character (=, +, -, @, tab or carriage return) with an apostrophe,
+// so spreadsheets show it as text instead of running it as a formula.
+func csvCell(text string) string {
+	if text != "" && strings.ContainsRune("=+-@\t\r", rune(text[0])) {
+		return "'" + text
+	}
+
+	return text
+}
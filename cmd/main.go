@@ -1,16 +1,95 @@
 package main
 
 import (
+	"flag"
+	"fmt"
+	"os"
+
 	"communications/internal/config"
-	"communications/internal/database"
-	"communications/internal/handlers"
-	"communications/internal/server"
 )
 
+// Usage of the binary, printed by the help command and the -h flag.
+const usage = `Usage: communications [--config <file>] [--print-config] <command> [arguments]
+
+Commands:
+  serve                        Run the HTTP server (default when no command is given)
+  migrate up                   Apply all pending SQL migrations
+  migrate down [steps]         Roll back the given number of migrations (default 1)
+  migrate version              Show the current migration version
+  migrate force <version>      Set the migration version and clear the dirty flag
+  client add [flags]           Add a client (--name, --email, --phone, --country, --website, --email-quota, --sms-quota)
+  client list                  List all clients
+  client check <id>            Check if a client's email and phone can receive notifications
+  client verify <id>           Mark a client's contacts as verified (--channel email, sms or both)
+  client resend <id>           Resend the verification link and code to a client's unverified contacts
+  client delete <id>           Soft-delete a client, keeping its leads
+  lead export [flags]          Export leads as CSV or JSON lines (--client, --from, --to, --format)
+  notify test --client <id>    Send a sample lead notification to a client (--channel email, sms or both)
+  config check                 Validate the configuration
+  config print                 Print the effective configuration with secrets redacted
+
+Flags:
+`
+
+// Runs a command with the loaded configuration and the arguments following the command name.
+type command func(cfg *config.Config, args []string) error
+
+// Commands of the binary keyed by name.
+var commands = map[string]command{
+	"serve":   serve,
+	"migrate": migrateCommand,
+	"client":  clientCommand,
+	"lead":    leadCommand,
+	"notify":  notifyCommand,
+	"config":  configCommand,
+}
+
 // Entry point for the application.
+// Flags: --config reads settings from a YAML/TOML file instead of CONFIG_FILE, and --print-config shows
+// the effective configuration with secrets redacted instead of running the command.
 func main() {
-	cfg := config.Load()
-	db := database.Connect(cfg)
-	router := handlers.Init(cfg, db)
-	server.Listen(cfg.Port, router)
+	flag.Usage = func() {
+		fmt.Fprint(flag.CommandLine.Output(), usage)
+		flag.PrintDefaults()
+	}
+
+	configPath := flag.String("config", "", "path to a YAML or TOML config file (overrides CONFIG_FILE)")
+	printConfig := flag.Bool("print-config", false, "print the effective configuration with secrets redacted and exit")
+	flag.Parse()
+
+	if err := run(*configPath, *printConfig, flag.Args()); err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		os.Exit(1)
+	}
+}
+
+// Resolves the command (serve by default), loads the configuration and runs the command.
+// Used to report every error from a single place instead of exiting mid-way.
+func run(configPath string, printConfig bool, args []string) error {
+	name := "serve"
+	if len(args) > 0 {
+		name, args = args[0], args[1:]
+	}
+
+	if name == "help" {
+		flag.CommandLine.SetOutput(os.Stdout)
+		flag.Usage()
+		return nil
+	}
+
+	cmd, ok := commands[name]
+	if !ok {
+		return fmt.Errorf("unknown command %q (run \"communications help\" for usage)", name)
+	}
+
+	cfg, err := config.Load(configPath)
+	if err != nil {
+		return err
+	}
+
+	if printConfig {
+		return cfg.Print(os.Stdout)
+	}
+
+	return cmd(cfg, args)
 }
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"communications/internal/config"
+	"communications/internal/database"
+
+	"github.com/golang-migrate/migrate/v4"
+)
+
+// Manages the SQL migrations: up, down [steps], version and force <version>.
+// Holds the migration lock, so it never runs concurrently with a replica migrating at startup.
+func migrateCommand(cfg *config.Config, args []string) error {
+	if len(args) == 0 {
+		return errors.New("usage: migrate up|down [steps]|version|force <version>")
+	}
+
+	var action func(migration *migrate.Migrate) error
+
+	switch args[0] {
+	case "up":
+		action = (*migrate.Migrate).Up
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			var err error
+			if steps, err = strconv.Atoi(args[1]); err != nil || steps < 1 {
+				return fmt.Errorf("steps must be a positive number, got %q", args[1])
+			}
+		}
+		action = func(migration *migrate.Migrate) error { return migration.Steps(-steps) }
+	case "version":
+		action = func(migration *migrate.Migrate) error { return nil }
+	case "force":
+		if len(args) < 2 {
+			return errors.New("usage: migrate force <version>")
+		}
+		version, err := strconv.Atoi(args[1])
+		if err != nil {
+			return fmt.Errorf("version must be a number, got %q", args[1])
+		}
+		action = func(migration *migrate.Migrate) error { return migration.Force(version) }
+	default:
+		return fmt.Errorf("unknown migrate command %q", args[0])
+	}
+
+	db, err := database.Connect(cfg)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	return database.WithMigrationLock(context.Background(), db, func() error {
+		migration, err := database.NewMigration(cfg)
+		if err != nil {
+			return err
+		}
+		defer migration.Close()
+
+		if err := action(migration); errors.Is(err, migrate.ErrNoChange) {
+			fmt.Println("No migrations to apply.")
+		} else if err != nil {
+			return err
+		}
+
+		version, dirty, err := migration.Version()
+		if errors.Is(err, migrate.ErrNilVersion) {
+			fmt.Println("No migrations applied.")
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("Migration version: %d (dirty: %t)\n", version, dirty)
+
+		return nil
+	})
+}
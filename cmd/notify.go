@@ -0,0 +1,86 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+
+	"communications/internal/config"
+	"communications/internal/database/dto"
+)
+
+// Message of the sample lead sent by the notify test command.
+var sampleMessage = "This is a test notification sent by the notify test command."
+
+// Sample lead sent by the notify test command.
+var sampleLead = dto.CreateLeadDTO{
+	Name:    "Test Lead",
+	Email:   "test.lead@example.com",
+	Phone:   "+12025550123",
+	Message: &sampleMessage,
+}
+
+// Sends a sample lead notification to a client, to check the provider setup and the client's contacts.
+// Test notifications are not counted towards the client's quota.
+func notifyCommand(cfg *config.Config, args []string) error {
+	if len(args) == 0 || args[0] != "test" {
+		return errors.New("usage: notify test --client <id> [--channel email|sms|both]")
+	}
+
+	flags := flag.NewFlagSet("notify test", flag.ContinueOnError)
+	clientID := flags.String("client", "", "ID of the client to notify (required)")
+	channel := flags.String("channel", "both", "channel to test: email, sms or both")
+
+	if err := flags.Parse(args[1:]); err != nil {
+		return err
+	}
+	if *channel != "email" && *channel != "sms" && *channel != "both" {
+		return fmt.Errorf("unknown channel %q (use email, sms or both)", *channel)
+	}
+
+	id, err := parseClientID([]string{*clientID})
+	if err != nil {
+		return err
+	}
+
+	service, db, err := connect(cfg)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	ctx := context.Background()
+
+	client, err := service.Clients.FindClient(ctx, id)
+	if err != nil {
+		return fmt.Errorf("unable to find client %s: %w", id, err)
+	}
+
+	var errs []error
+	lead := sampleLead
+
+	if *channel != "sms" {
+		id, err := service.SendEmail(ctx, &client.Email, &lead)
+		report("Email", client.Email, id, err)
+		errs = append(errs, err)
+	}
+
+	if *channel != "email" {
+		id, err := service.SendSMS(ctx, &client.Phone, &lead)
+		report("SMS", client.Phone, id, err)
+		errs = append(errs, err)
+	}
+
+	return errors.Join(errs...)
+}
+
+// Prints the result of a test notification with the message ID returned by Azure.
+func report(channel, to, id string, err error) {
+	if err != nil {
+		fmt.Printf("%s to %s failed: %v\n", channel, to, err)
+		return
+	}
+
+	fmt.Printf("%s to %s sent (message ID %s).\n", channel, to, id)
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"communications/internal/config"
+	"communications/internal/database"
+	"communications/internal/handlers"
+	"communications/internal/logger"
+	"communications/internal/repository"
+	"communications/internal/server"
+	"communications/internal/tracing"
+)
+
+// Starts all components, serves HTTP until SIGINT or SIGTERM, and then shuts everything down in order.
+func serve(cfg *config.Config, args []string) error {
+	if len(args) > 0 {
+		return errors.New("serve takes no arguments")
+	}
+	if err := cfg.RequireVerificationSecret(); err != nil {
+		return err
+	}
+
+	logger.Init(cfg.LogLevel)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	lifecycle := server.NewLifecycle()
+
+	shutdownTracing, err := tracing.Init(context.Background(), cfg.TracingExporter)
+	if err != nil {
+		return err
+	}
+	lifecycle.OnStop("tracing", shutdownTracing)
+
+	db, err := database.Connect(cfg)
+	if err != nil {
+		return errors.Join(err, lifecycle.Shutdown(context.Background()))
+	}
+	lifecycle.OnStop("database pool", func(ctx context.Context) error {
+		db.Close()
+		return nil
+	})
+
+	if cfg.AutoMigrate {
+		if err := database.Migrate(ctx, cfg, db); err != nil {
+			return errors.Join(err, lifecycle.Shutdown(context.Background()))
+		}
+	}
+
+	router, err := handlers.Init(cfg, db, repository.NewPostgres(db), lifecycle)
+	if err != nil {
+		return errors.Join(err, lifecycle.Shutdown(context.Background()))
+	}
+
+	return server.Listen(ctx, cfg.Port, router, lifecycle, time.Duration(cfg.ShutdownTimeout)*time.Second)
+}
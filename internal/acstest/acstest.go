@@ -0,0 +1,208 @@
+// Package acstest provides a fake Azure Communication Services server for tests.
+// It accepts the email and SMS requests sent by the services package, records them, and can be told
+// to fail or to respond slowly, so tests never call the real provider. Email operations stay running
+// until a test completes them.
+package acstest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"communications/internal/database/dto"
+)
+
+// Channels served by the fake.
+const (
+	Email = "email" // POST /emails:send, GET /emails/operations/{id}
+	SMS   = "sms"   // POST /sms
+)
+
+// Access key of the fake's connection string; requests with another key are rejected with 401.
+const AccessKey = "fake-access-key"
+
+// Request received by the fake server.
+type Request struct {
+	Channel string            // Email or SMS.
+	Email   *dto.EmailMessage // Decoded payload of email requests.
+	SMS     *dto.SMSMessage   // Decoded payload of SMS requests.
+}
+
+// Response of the fake server for a channel.
+type Response struct {
+	Status int           // HTTP status code; 202 Accepted when zero.
+	Delay  time.Duration // Time to wait before responding (e.g. to trigger client timeouts).
+}
+
+// Fake Azure Communication Services server.
+type Server struct {
+	*httptest.Server
+
+	mutex      sync.Mutex
+	requests   []Request
+	responses  map[string]Response
+	operations map[string]string // Status of email operations by ID.
+}
+
+// Starts a fake server that accepts every request, and stops it when the test ends.
+func NewServer(t testing.TB) *Server {
+	s := &Server{responses: map[string]Response{}, operations: map[string]string{}}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serve))
+	t.Cleanup(s.Close)
+
+	return s
+}
+
+// Returns the connection string (AZURE_URL) pointing to the fake.
+func (s *Server) ConnectionString() string {
+	return "endpoint=" + s.URL + ";accesskey=" + AccessKey
+}
+
+// Sets how the fake responds to the channel's requests from now on.
+func (s *Server) Respond(channel string, response Response) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.responses[channel] = response
+}
+
+// Makes the channel's requests fail with the given HTTP status code.
+func (s *Server) Fail(channel string, status int) {
+	s.Respond(channel, Response{Status: status})
+}
+
+// Sets the status of an email operation (e.g. "Succeeded" or "Failed"), returned when the operation is polled.
+// Failed and canceled operations are reported with an error.
+func (s *Server) CompleteOperation(id, status string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.operations[id] = status
+}
+
+// Returns the requests received for the channel, or all requests if the channel is empty.
+func (s *Server) Requests(channel string) []Request {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if channel == "" {
+		return slices.Clone(s.requests)
+	}
+
+	var requests []Request
+	for _, request := range s.requests {
+		if request.Channel == channel {
+			requests = append(requests, request)
+		}
+	}
+
+	return requests
+}
+
+// Records the request and responds as configured for its channel, mimicking the ACS response bodies.
+func (s *Server) serve(w http.ResponseWriter, r *http.Request) {
+	request := Request{}
+
+	switch {
+	case r.Method == http.MethodGet && strings.HasPrefix(r.URL.Path, "/emails/operations/"):
+		s.serveOperation(w, r, strings.TrimPrefix(r.URL.Path, "/emails/operations/"))
+		return
+	case r.Method == http.MethodPost && r.URL.Path == "/emails:send":
+		request.Channel, request.Email = Email, &dto.EmailMessage{}
+	case r.Method == http.MethodPost && r.URL.Path == "/sms":
+		request.Channel, request.SMS = SMS, &dto.SMSMessage{}
+	default:
+		http.NotFound(w, r)
+		return
+	}
+
+	if r.Header.Get("api-key") != AccessKey {
+		writeJSON(w, http.StatusUnauthorized, map[string]any{"error": map[string]string{"code": "Unauthorized"}})
+		return
+	}
+
+	var payload any = request.Email
+	if request.SMS != nil {
+		payload = request.SMS
+	}
+	if err := json.NewDecoder(r.Body).Decode(payload); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]any{"error": map[string]string{"code": "BadRequest", "message": err.Error()}})
+		return
+	}
+
+	s.mutex.Lock()
+	s.requests = append(s.requests, request)
+	id := fmt.Sprintf("%s-%d", request.Channel, len(s.requests))
+	response := s.responses[request.Channel]
+	s.mutex.Unlock()
+
+	if response.Delay > 0 {
+		select {
+		case <-time.After(response.Delay):
+		case <-r.Context().Done():
+			return
+		}
+	}
+
+	status := response.Status
+	if status == 0 {
+		status = http.StatusAccepted
+	}
+	if status >= 300 {
+		writeJSON(w, status, map[string]any{"error": map[string]string{"code": http.StatusText(status)}})
+		return
+	}
+
+	if request.Channel == Email {
+		s.mutex.Lock()
+		s.operations[id] = "Running"
+		s.mutex.Unlock()
+
+		w.Header().Set("Operation-Location", s.URL+"/emails/operations/"+id+"?api-version=2023-03-31")
+		writeJSON(w, status, map[string]string{"id": id, "status": "Running"})
+		return
+	}
+
+	results := []map[string]any{}
+	for _, to := range request.SMS.To {
+		results = append(results, map[string]any{"to": to, "messageId": id, "httpStatusCode": http.StatusAccepted, "successful": true})
+	}
+	writeJSON(w, status, map[string]any{"value": results})
+}
+
+// Responds with the status of an email operation, or 404 if the fake never created it.
+func (s *Server) serveOperation(w http.ResponseWriter, r *http.Request, id string) {
+	if r.Header.Get("api-key") != AccessKey {
+		writeJSON(w, http.StatusUnauthorized, map[string]any{"error": map[string]string{"code": "Unauthorized"}})
+		return
+	}
+
+	s.mutex.Lock()
+	status, ok := s.operations[id]
+	s.mutex.Unlock()
+
+	if !ok {
+		writeJSON(w, http.StatusNotFound, map[string]any{"error": map[string]string{"code": "NotFound"}})
+		return
+	}
+
+	body := map[string]any{"id": id, "status": status}
+	if status == "Failed" || status == "Canceled" {
+		body["error"] = map[string]string{"code": "EmailDroppedAllRecipientsSuppressed", "message": "Message was not sent."}
+	}
+
+	writeJSON(w, http.StatusOK, body)
+}
+
+// Writes a JSON response with the given status code.
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
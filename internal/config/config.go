@@ -2,77 +2,358 @@ package config
 
 import (
 	"communications/internal/utils"
-	"log"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/url"
 	"os"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/joho/godotenv"
+	"gopkg.in/yaml.v3"
 )
 
 // Holds all environment variables and settings required to run the application.
 // Populated once at startup and used throughout the app for configuration.
 type Config struct {
-	Port             string   // Port the server listens on.
-	ThrottleTTL      int      // Time-to-live for request throttling (seconds).
-	ThrottleLimit    int      // Maximum requests allowed per TTL.
-	GinMode          string   // Gin framework mode (debug, release, etc.).
-	AllowedOrigins   []string // List of allowed CORS origins.
-	DatabaseHost     string   // PostgreSQL host.
-	DatabasePort     int      // PostgreSQL port.
-	DatabaseUser     string   // PostgreSQL user.
-	DatabasePassword string   // PostgreSQL password.
-	DatabaseName     string   // PostgreSQL database name.
-	DatabaseSSL      string   // PostgreSQL SSL mode.
-	AzureURL         string   // Azure service endpoint.
-	EmailFrom        string   // Default sender Email address.
-	SMSFrom          string   // Default sender SMS address.
-}
-
-// Loads environment variables from .env (if present) or from the environment, validates required variables, and sets server timezone to UTC.
+	Port               string   `yaml:"port"`                     // Port the server listens on.
+	ShutdownTimeout    int      `yaml:"shutdown_timeout"`         // Grace period for finishing in-flight work on shutdown (seconds).
+	ThrottleTTL        int      `yaml:"throttle_ttl"`             // Time-to-live for request throttling (seconds).
+	ThrottleLimit      int      `yaml:"throttle_limit"`           // Maximum requests allowed per TTL.
+	RateLimiter        string   `yaml:"rate_limiter"`             // Rate limiter backend (memory, postgres).
+	ClientTTL          int      `yaml:"client_throttle_ttl"`      // Time to regain one lead per client (seconds).
+	ClientLimit        int      `yaml:"client_throttle_limit"`    // Maximum leads per client at once (0 disables the limit).
+	SubmitterTTL       int      `yaml:"submitter_throttle_ttl"`   // Time to regain one lead per submitter email/phone (seconds).
+	SubmitterLimit     int      `yaml:"submitter_throttle_limit"` // Maximum leads per submitter email/phone at once (0 disables the limit).
+	GinMode            string   `yaml:"gin_mode"`                 // Gin framework mode (debug, release, test).
+	LogLevel           string   `yaml:"log_level"`                // Minimum level of logged messages (debug, info, warn, error).
+	TracingExporter    string   `yaml:"tracing_exporter"`         // OpenTelemetry span exporter (none, otlp, stdout).
+	AllowedOrigins     []string `yaml:"allowed_origins"`          // List of allowed CORS origins.
+	DatabaseHost       string   `yaml:"postgres_host"`            // PostgreSQL host.
+	DatabasePort       int      `yaml:"postgres_port"`            // PostgreSQL port.
+	DatabaseUser       string   `yaml:"postgres_user"`            // PostgreSQL user.
+	DatabasePassword   string   `yaml:"postgres_password"`        // PostgreSQL password.
+	DatabaseName       string   `yaml:"postgres_db"`              // PostgreSQL database name.
+	DatabaseSSL        string   `yaml:"postgres_ssl"`             // PostgreSQL SSL mode.
+	AutoMigrate        bool     `yaml:"auto_migrate"`             // Applies pending SQL migrations when the server starts.
+	AzureURL           string   `yaml:"azure_url"`                // Azure service endpoint.
+	ProviderTimeout    int      `yaml:"provider_timeout"`         // Timeout of outbound provider calls (seconds).
+	EmailPollInterval  int      `yaml:"email_poll_interval"`      // Interval of polling pending Azure email operations (seconds, 0 disables polling).
+	EmailFrom          string   `yaml:"email_from"`               // Default sender Email address.
+	SMSFrom            string   `yaml:"sms_from"`                 // Default sender SMS address.
+	EmailQuota         int      `yaml:"email_quota"`              // Default monthly email quota per client (0 means unlimited).
+	SMSQuota           int      `yaml:"sms_quota"`                // Default monthly SMS quota per client (0 means unlimited).
+	QuotaMode          string   `yaml:"quota_mode"`               // Behavior when a quota is exhausted (block, degrade).
+	AdminToken         string   `yaml:"admin_token"`              // Bearer token for the admin API (admin API is disabled if empty).
+	MetricsToken       string   `yaml:"metrics_token"`            // Optional bearer token protecting the metrics endpoint.
+	WebhookToken       string   `yaml:"webhook_token"`            // Token expected in the Event Grid webhook URL (webhook is disabled if empty).
+	ReplyForwarding    string   `yaml:"reply_forwarding"`         // Forwarding of client SMS replies to the lead (off, email, sms).
+	ReplyWindow        int      `yaml:"reply_window"`             // Time after a lead SMS during which client replies are linked to the lead (seconds).
+	SMSHelpMessage     string   `yaml:"sms_help_message"`         // Answer to the HELP keyword.
+	EmailCheck         string   `yaml:"email_check"`              // Submitter email deliverability check mode (off, flag, reject).
+	DisposableEmails   []string `yaml:"disposable_email_domains"` // Email domains considered disposable by the deliverability check.
+	TrustedProxies     []string `yaml:"trusted_proxies"`          // Proxy IPs/CIDRs whose forwarding headers are trusted for the client IP.
+	AnonymizeIP        bool     `yaml:"anonymize_ip"`             // Truncates stored submitter IPs (IPv4 to /24, IPv6 to /48).
+	GeoIPDatabase      string   `yaml:"geoip_database"`           // Optional path to a MaxMind-format GeoIP database.
+	PublicURL          string   `yaml:"public_url"`               // Public base URL of the API, used in verification links.
+	VerificationSecret string   `yaml:"verification_secret"`      // Secret signing verification links and hashing verification codes.
+	LinkTTL            int      `yaml:"verification_link_ttl"`    // Lifetime of email verification links (seconds).
+	CodeTTL            int      `yaml:"verification_code_ttl"`    // Lifetime of SMS verification codes (seconds).
+	UnverifiedMode     string   `yaml:"unverified_mode"`          // Behavior for unverified client contacts (block, warn).
+}
+
+// Loads settings from the environment (and .env if present), falling back to the optional YAML/TOML config file
+// at path (or CONFIG_FILE) and then to defaults, validates them, and sets server timezone to UTC.
 // Called once at startup to initialize application configuration.
-// Returns a pointer to the populated Config struct, or terminates the app if required variables are missing.
-func Load() *Config {
+// Returns a pointer to the populated Config struct, or an error listing every missing or invalid setting.
+func Load(path string) (*Config, error) {
 	godotenv.Load(".env")
 
-	required := []string{
-		"PORT",
-		"THROTTLE_TTL",
-		"THROTTLE_LIMIT",
-		"GIN_MODE",
-		"ALLOWED_ORIGINS",
-		"POSTGRES_HOST",
-		"POSTGRES_PORT",
-		"POSTGRES_USER",
-		"POSTGRES_PASSWORD",
-		"POSTGRES_DB",
-		"POSTGRES_SSL",
-		"AZURE_URL",
-		"EMAIL_FROM",
-		"SMS_FROM",
-	}
-
-	for _, key := range required {
-		if os.Getenv(key) == "" {
-			log.Fatalf("Environment variable %s must be set", key)
+	return load(path, os.Getenv)
+}
+
+// Loads the configuration like Load, reading the environment through getenv.
+// Used by tests to load from a fixed environment instead of the process environment.
+func load(path string, getenv func(string) string) (*Config, error) {
+	if path == "" {
+		path = getenv("CONFIG_FILE")
+	}
+
+	l := &loader{getenv: getenv, seen: map[string]bool{}}
+
+	if path != "" {
+		file, err := readFile(path)
+		if err != nil {
+			return nil, err
+		}
+		l.file = file
+	}
+
+	port := strconv.Itoa(l.int("PORT", 5000, 1, 65535))
+	ginMode := l.oneOf("GIN_MODE", "release", "debug", "test")
+
+	cfg := &Config{
+		Port:               port,
+		ShutdownTimeout:    l.int("SHUTDOWN_TIMEOUT", 15, 1, 3600),
+		ThrottleTTL:        l.int("THROTTLE_TTL", 60, 1, 86400),
+		ThrottleLimit:      l.int("THROTTLE_LIMIT", 10, 1, 1000000),
+		RateLimiter:        l.oneOf("RATE_LIMITER", "memory", "postgres"),
+		ClientTTL:          l.int("CLIENT_THROTTLE_TTL", 0, 0, 86400),
+		ClientLimit:        l.int("CLIENT_THROTTLE_LIMIT", 0, 0, 1000000),
+		SubmitterTTL:       l.int("SUBMITTER_THROTTLE_TTL", 0, 0, 86400),
+		SubmitterLimit:     l.int("SUBMITTER_THROTTLE_LIMIT", 0, 0, 1000000),
+		GinMode:            ginMode,
+		LogLevel:           l.oneOf("LOG_LEVEL", "info", "debug", "warn", "error"),
+		TracingExporter:    l.oneOf("TRACING_EXPORTER", "none", "otlp", "stdout"),
+		AllowedOrigins:     l.list("ALLOWED_ORIGINS", "", true),
+		DatabaseHost:       l.string("POSTGRES_HOST", "localhost"),
+		DatabasePort:       l.int("POSTGRES_PORT", 5432, 1, 65535),
+		DatabaseUser:       l.required("POSTGRES_USER"),
+		DatabasePassword:   l.required("POSTGRES_PASSWORD"),
+		DatabaseName:       l.required("POSTGRES_DB"),
+		DatabaseSSL:        l.oneOf("POSTGRES_SSL", "disable", "allow", "prefer", "require", "verify-ca", "verify-full"),
+		AutoMigrate:        l.bool("AUTO_MIGRATE", false),
+		AzureURL:           l.required("AZURE_URL"),
+		ProviderTimeout:    l.int("PROVIDER_TIMEOUT", 10, 1, 300),
+		EmailPollInterval:  l.int("EMAIL_POLL_INTERVAL", 30, 0, 3600),
+		EmailFrom:          l.required("EMAIL_FROM"),
+		SMSFrom:            l.required("SMS_FROM"),
+		EmailQuota:         l.int("EMAIL_QUOTA", 0, 0, math.MaxInt32),
+		SMSQuota:           l.int("SMS_QUOTA", 0, 0, math.MaxInt32),
+		QuotaMode:          l.oneOf("QUOTA_MODE", "degrade", "block"),
+		AdminToken:         l.string("ADMIN_TOKEN", ""),
+		MetricsToken:       l.string("METRICS_TOKEN", ""),
+		WebhookToken:       l.string("WEBHOOK_TOKEN", ""),
+		ReplyForwarding:    l.oneOf("REPLY_FORWARDING", "off", "email", "sms"),
+		ReplyWindow:        l.int("REPLY_WINDOW", 604800, 3600, 2592000),
+		SMSHelpMessage:     l.string("SMS_HELP_MESSAGE", defaultHelpMessage),
+		EmailCheck:         l.oneOf("EMAIL_CHECK", "off", "flag", "reject"),
+		DisposableEmails:   l.list("DISPOSABLE_EMAIL_DOMAINS", defaultDisposableEmails, false),
+		TrustedProxies:     l.list("TRUSTED_PROXIES", "", false),
+		AnonymizeIP:        l.bool("ANONYMIZE_IP", false),
+		GeoIPDatabase:      l.string("GEOIP_DATABASE", ""),
+		PublicURL:          strings.TrimSuffix(l.publicURL("PUBLIC_URL", ginMode, "http://localhost:"+port), "/"),
+		VerificationSecret: l.secret("VERIFICATION_SECRET", minSecretLength),
+		LinkTTL:            l.int("VERIFICATION_LINK_TTL", 172800, 60, 2592000),
+		CodeTTL:            l.int("VERIFICATION_CODE_TTL", 900, 60, 86400),
+		UnverifiedMode:     l.oneOf("UNVERIFIED_MODE", "block", "warn"),
+	}
+
+	for key := range l.file {
+		if !l.seen[key] {
+			l.fail(key, fmt.Errorf("unknown setting in %s", path))
 		}
 	}
 
+	if len(l.errs) > 0 {
+		return nil, fmt.Errorf("invalid configuration:\n%w", errors.Join(l.errs...))
+	}
+
 	time.Local = time.UTC
 
-	return &Config{
-		Port:             os.Getenv("PORT"),
-		ThrottleTTL:      utils.StringToNumber[int](os.Getenv("THROTTLE_TTL")),
-		ThrottleLimit:    utils.StringToNumber[int](os.Getenv("THROTTLE_LIMIT")),
-		GinMode:          os.Getenv("GIN_MODE"),
-		AllowedOrigins:   utils.SplitString(os.Getenv("ALLOWED_ORIGINS"), ","),
-		DatabaseHost:     os.Getenv("POSTGRES_HOST"),
-		DatabasePort:     utils.StringToNumber[int](os.Getenv("POSTGRES_PORT")),
-		DatabaseUser:     os.Getenv("POSTGRES_USER"),
-		DatabasePassword: os.Getenv("POSTGRES_PASSWORD"),
-		DatabaseName:     os.Getenv("POSTGRES_DB"),
-		DatabaseSSL:      os.Getenv("POSTGRES_SSL"),
-		AzureURL:         os.Getenv("AZURE_URL"),
-		EmailFrom:        os.Getenv("EMAIL_FROM"),
-		SMSFrom:          os.Getenv("SMS_FROM"),
+	return cfg, nil
+}
+
+// Minimum length of VERIFICATION_SECRET, so signed links and code hashes cannot be brute-forced.
+const minSecretLength = 32
+
+// Returns an error if VERIFICATION_SECRET is not set. Checked only by the commands that sign verification links
+// and hash codes (serve, client add and client resend), so the other commands run without the secret.
+func (c *Config) RequireVerificationSecret() error {
+	if c.VerificationSecret == "" {
+		return errors.New("invalid configuration:\nVERIFICATION_SECRET: must be set")
+	}
+
+	return nil
+}
+
+// Built-in list of well-known disposable email providers.
+// Used when DISPOSABLE_EMAIL_DOMAINS is not set.
+const defaultDisposableEmails = "mailinator.com,guerrillamail.com,sharklasers.com,10minutemail.com,tempmail.com,temp-mail.org,yopmail.com,trashmail.com,getnada.com,dispostable.com,maildrop.cc,throwawaymail.com"
+
+// Built-in answer to the HELP keyword of inbound SMS.
+// Used when SMS_HELP_MESSAGE is not set.
+const defaultHelpMessage = "You receive website lead notifications from this number. Msg & data rates may apply. Reply STOP to unsubscribe, START to resubscribe."
+
+// Placeholder shown instead of secret values.
+const redacted = "[REDACTED]"
+
+// Matches the access key of an Azure connection string, so the endpoint stays visible when redacting it.
+var accessKeyRegExp = regexp.MustCompile(`(?i)(accesskey=)[^;]*`)
+
+// Returns a copy of the config with passwords, tokens and access keys replaced by a placeholder.
+// Used to show the effective configuration without leaking secrets.
+func (c Config) Redacted() Config {
+	redact := func(value string) string {
+		if value == "" {
+			return ""
+		}
+		return redacted
+	}
+
+	c.DatabasePassword = redact(c.DatabasePassword)
+	c.AdminToken = redact(c.AdminToken)
+	c.MetricsToken = redact(c.MetricsToken)
+	c.WebhookToken = redact(c.WebhookToken)
+	c.VerificationSecret = redact(c.VerificationSecret)
+	c.AzureURL = accessKeyRegExp.ReplaceAllString(c.AzureURL, "${1}"+redacted)
+
+	return c
+}
+
+// Writes the effective configuration as YAML with secrets redacted.
+// The output uses the config file keys, so it can be saved and used as a config file.
+func (c Config) Print(w io.Writer) error {
+	encoder := yaml.NewEncoder(w)
+	encoder.SetIndent(2)
+	defer encoder.Close()
+
+	return encoder.Encode(c.Redacted())
+}
+
+// Collects settings from the environment, the config file and defaults, and remembers every invalid one.
+// Used to report all configuration problems at once instead of stopping at the first.
+type loader struct {
+	getenv func(string) string // Reads the environment (os.Getenv outside of tests).
+	file   map[string]string   // Settings from the config file keyed by environment variable name.
+	seen   map[string]bool     // Keys that were looked up, used to detect unknown settings in the file.
+	errs   []error
+}
+
+// Returns the value of a setting, preferring the environment over the config file.
+// Empty values count as unset.
+func (l *loader) lookup(key string) (string, bool) {
+	l.seen[key] = true
+
+	if value := strings.TrimSpace(l.getenv(key)); value != "" {
+		return value, true
+	}
+	if value := strings.TrimSpace(l.file[key]); value != "" {
+		return value, true
+	}
+
+	return "", false
+}
+
+// Records an invalid setting.
+func (l *loader) fail(key string, err error) {
+	l.errs = append(l.errs, fmt.Errorf("%s: %w", key, err))
+}
+
+// Returns the value of an optional setting, or the fallback if it is not set.
+func (l *loader) string(key, fallback string) string {
+	if value, ok := l.lookup(key); ok {
+		return value
+	}
+
+	return fallback
+}
+
+// Returns the value of a setting that has no sensible default.
+func (l *loader) required(key string) string {
+	value, ok := l.lookup(key)
+	if !ok {
+		l.fail(key, errors.New("must be set"))
+	}
+
+	return value
+}
+
+// Returns the value of an optional secret that must be at least min characters long when set.
+func (l *loader) secret(key string, min int) string {
+	value := l.string(key, "")
+	if value != "" && len(value) < min {
+		l.fail(key, fmt.Errorf("must be at least %d characters long", min))
+	}
+
+	return value
+}
+
+// Returns an absolute http(s) URL. It is required in release mode, since a localhost fallback would send
+// dead links to clients; other modes (debug, test) fall back to the local address.
+func (l *loader) publicURL(key, ginMode, fallback string) string {
+	value := l.string(key, fallback)
+	if ginMode == "release" {
+		value = l.required(key)
 	}
+
+	if parsed, err := url.Parse(value); value != "" && (err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "") {
+		l.fail(key, fmt.Errorf("%q must be an absolute http(s) URL", value))
+	}
+
+	return value
+}
+
+// Returns the value of a setting limited to the fallback and the allowed values (case-insensitive).
+func (l *loader) oneOf(key, fallback string, allowed ...string) string {
+	value, ok := l.lookup(key)
+	if !ok {
+		return fallback
+	}
+
+	value = strings.ToLower(value)
+	allowed = append([]string{fallback}, allowed...)
+
+	if !slices.Contains(allowed, value) {
+		l.fail(key, fmt.Errorf("%q must be one of %s", value, strings.Join(allowed, ", ")))
+	}
+
+	return value
+}
+
+// Returns the integer value of a setting within [min, max], or the fallback if it is not set.
+func (l *loader) int(key string, fallback, min, max int) int {
+	value, ok := l.lookup(key)
+	if !ok {
+		return fallback
+	}
+
+	number, err := utils.StringToNumber[int](value)
+	if err != nil {
+		l.fail(key, err)
+		return fallback
+	}
+	if number < min || number > max {
+		l.fail(key, fmt.Errorf("%d must be between %d and %d", number, min, max))
+	}
+
+	return number
+}
+
+// Returns the boolean value of a setting (true/false, 1/0), or the fallback if it is not set.
+func (l *loader) bool(key string, fallback bool) bool {
+	value, ok := l.lookup(key)
+	if !ok {
+		return fallback
+	}
+
+	boolean, err := strconv.ParseBool(value)
+	if err != nil {
+		l.fail(key, fmt.Errorf("%q is not a valid boolean", value))
+		return fallback
+	}
+
+	return boolean
+}
+
+// Returns the comma-separated values of a setting, or the values of the fallback if it is not set.
+// Returns nil for an unset optional list without a fallback.
+func (l *loader) list(key, fallback string, required bool) []string {
+	value, ok := l.lookup(key)
+	if !ok && required {
+		l.fail(key, errors.New("must be set"))
+	}
+	if !ok {
+		value = fallback
+	}
+	if value == "" {
+		return nil
+	}
+
+	return utils.SplitString(value, ",")
 }
@@ -0,0 +1,214 @@
+package config
+
+import (
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// Settings that have no default, so each test only changes what it checks.
+var requiredEnv = map[string]string{
+	"ALLOWED_ORIGINS":     "http://localhost:3000",
+	"POSTGRES_USER":       "postgres",
+	"POSTGRES_PASSWORD":   "db-password",
+	"POSTGRES_DB":         "communications",
+	"AZURE_URL":           "endpoint=https://example.communication.azure.com;accesskey=abc123",
+	"EMAIL_FROM":          "noreply@example.com",
+	"SMS_FROM":            "+12345678901",
+	"PUBLIC_URL":          "https://api.example.com/",
+	"VERIFICATION_SECRET": "0123456789abcdef0123456789abcdef",
+}
+
+// Loads the config file at path with the required settings and the given ones as the only environment,
+// so the tests do not depend on the environment they run in. Empty values unset a setting.
+func loadEnv(path string, env map[string]string) (*Config, error) {
+	settings := maps.Clone(requiredEnv)
+	maps.Copy(settings, env)
+
+	return load(path, func(key string) string { return settings[key] })
+}
+
+// Checks if invalid settings are reported together with descriptive messages.
+func TestLoadErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		env    map[string]string
+		errors []string
+	}{
+		{"Missing required", map[string]string{"POSTGRES_USER": "", "SMS_FROM": ""}, []string{"POSTGRES_USER: must be set", "SMS_FROM: must be set"}},
+		{"Garbage number", map[string]string{"THROTTLE_TTL": "abc"}, []string{`THROTTLE_TTL: "abc" is not a valid int`}},
+		{"Number out of range", map[string]string{"PORT": "70000", "THROTTLE_LIMIT": "0"}, []string{"PORT: 70000 must be between 1 and 65535", "THROTTLE_LIMIT: 0 must be between"}},
+		{"Unknown mode", map[string]string{"RATE_LIMITER": "redis"}, []string{`RATE_LIMITER: "redis" must be one of memory, postgres`}},
+		{"Unknown unverified mode", map[string]string{"UNVERIFIED_MODE": "ignore"}, []string{`UNVERIFIED_MODE: "ignore" must be one of block, warn`}},
+		{"Invalid boolean", map[string]string{"ANONYMIZE_IP": "maybe"}, []string{`ANONYMIZE_IP: "maybe" is not a valid boolean`}},
+		{"Short verification secret", map[string]string{"VERIFICATION_SECRET": "signing-key"}, []string{"VERIFICATION_SECRET: must be at least 32 characters long"}},
+		{"Missing public URL in release mode", map[string]string{"PUBLIC_URL": ""}, []string{"PUBLIC_URL: must be set"}},
+		{"Relative public URL", map[string]string{"PUBLIC_URL": "api.example.com"}, []string{`PUBLIC_URL: "api.example.com" must be an absolute http(s) URL`}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := loadEnv("", tt.env)
+			if err == nil {
+				t.Fatalf("Load() error = nil, want %v", tt.errors)
+			}
+
+			for _, message := range tt.errors {
+				if !strings.Contains(err.Error(), message) {
+					t.Errorf("Load() error = %q, want it to contain %q", err, message)
+				}
+			}
+		})
+	}
+}
+
+// Checks if optional settings fall back to their defaults.
+func TestLoadDefaults(t *testing.T) {
+	cfg, err := loadEnv("", nil)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	if cfg.Port != "5000" || cfg.ThrottleTTL != 60 || cfg.ThrottleLimit != 10 || cfg.GinMode != "release" {
+		t.Errorf("server defaults = %s/%d/%d/%s", cfg.Port, cfg.ThrottleTTL, cfg.ThrottleLimit, cfg.GinMode)
+	}
+	if cfg.DatabaseHost != "localhost" || cfg.DatabasePort != 5432 || cfg.DatabaseSSL != "disable" {
+		t.Errorf("database defaults = %s/%d/%s", cfg.DatabaseHost, cfg.DatabasePort, cfg.DatabaseSSL)
+	}
+	if cfg.PublicURL != "https://api.example.com" || cfg.LinkTTL != 172800 || cfg.CodeTTL != 900 || cfg.UnverifiedMode != "block" {
+		t.Errorf("verification defaults = %s/%d/%d/%s", cfg.PublicURL, cfg.LinkTTL, cfg.CodeTTL, cfg.UnverifiedMode)
+	}
+	if cfg.EmailPollInterval != 30 {
+		t.Errorf("email poll interval default = %d", cfg.EmailPollInterval)
+	}
+	if cfg.ReplyForwarding != "off" || cfg.ReplyWindow != 604800 || !strings.Contains(cfg.SMSHelpMessage, "STOP") {
+		t.Errorf("reply defaults = %s/%d/%q", cfg.ReplyForwarding, cfg.ReplyWindow, cfg.SMSHelpMessage)
+	}
+	if cfg.TrustedProxies != nil || len(cfg.DisposableEmails) == 0 {
+		t.Errorf("list defaults = %v/%v", cfg.TrustedProxies, cfg.DisposableEmails)
+	}
+}
+
+// Checks if the public URL falls back to the local address outside of release mode.
+func TestLoadPublicURL(t *testing.T) {
+	cfg, err := loadEnv("", map[string]string{"PUBLIC_URL": "", "GIN_MODE": "debug"})
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	if cfg.PublicURL != "http://localhost:5000" {
+		t.Errorf("PublicURL = %q, want http://localhost:5000", cfg.PublicURL)
+	}
+}
+
+// Checks if YAML and TOML files are read and the environment overrides them.
+func TestLoadFile(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		content string
+	}{
+		{"YAML", "config.yaml", "port: 8080\nthrottle_ttl: 30\nrate_limiter: postgres\ntrusted_proxies:\n  - 10.0.0.0/8\n  - 127.0.0.1\n"},
+		{"TOML", "config.toml", "port = 8080\nthrottle_ttl = 30\nrate_limiter = \"postgres\"\ntrusted_proxies = [\"10.0.0.0/8\", \"127.0.0.1\"]\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), tt.file)
+			if err := os.WriteFile(path, []byte(tt.content), 0o600); err != nil {
+				t.Fatal(err)
+			}
+
+			cfg, err := loadEnv(path, map[string]string{"THROTTLE_TTL": "90"})
+			if err != nil {
+				t.Fatalf("Load() error = %v", err)
+			}
+
+			if cfg.Port != "8080" || cfg.RateLimiter != "postgres" {
+				t.Errorf("file settings = %s/%s, want 8080/postgres", cfg.Port, cfg.RateLimiter)
+			}
+			if cfg.ThrottleTTL != 90 {
+				t.Errorf("ThrottleTTL = %d, want the environment value 90", cfg.ThrottleTTL)
+			}
+			if !slices.Equal(cfg.TrustedProxies, []string{"10.0.0.0/8", "127.0.0.1"}) {
+				t.Errorf("TrustedProxies = %v", cfg.TrustedProxies)
+			}
+		})
+	}
+}
+
+// Checks if unknown keys and unsupported formats in the config file are rejected.
+func TestLoadFileErrors(t *testing.T) {
+	dir := t.TempDir()
+
+	unknown := filepath.Join(dir, "config.yaml")
+	os.WriteFile(unknown, []byte("prot: 8080\n"), 0o600)
+
+	if _, err := loadEnv(unknown, nil); err == nil || !strings.Contains(err.Error(), "PROT: unknown setting") {
+		t.Errorf("Load() error = %v, want an unknown setting error", err)
+	}
+
+	json := filepath.Join(dir, "config.json")
+	os.WriteFile(json, []byte("{}"), 0o600)
+
+	if _, err := loadEnv(json, nil); err == nil || !strings.Contains(err.Error(), "unsupported config file") {
+		t.Errorf("Load() error = %v, want an unsupported format error", err)
+	}
+}
+
+// Checks if secrets are hidden from the printed configuration.
+func TestPrint(t *testing.T) {
+	cfg, err := loadEnv("", map[string]string{"ADMIN_TOKEN": "admin-secret"})
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	var output strings.Builder
+	if err := cfg.Print(&output); err != nil {
+		t.Fatalf("Print() error = %v", err)
+	}
+
+	for _, secret := range []string{"db-password", "abc123", "admin-secret", "signing-key"} {
+		if strings.Contains(output.String(), secret) {
+			t.Errorf("Print() leaked %q:\n%s", secret, output.String())
+		}
+	}
+	if !strings.Contains(output.String(), "endpoint=https://example.communication.azure.com;accesskey=[REDACTED]") {
+		t.Errorf("Print() did not keep the Azure endpoint:\n%s", output.String())
+	}
+	if cfg.AdminToken != "admin-secret" {
+		t.Errorf("Print() changed the loaded config")
+	}
+}
+
+// Checks if the config loads without VERIFICATION_SECRET, and only the commands that sign require it.
+func TestRequireVerificationSecret(t *testing.T) {
+	tests := []struct {
+		name    string
+		secret  string
+		wantErr bool
+	}{
+		{"Set", "0123456789abcdef0123456789abcdef", false},
+		{"Missing", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, err := loadEnv("", map[string]string{"VERIFICATION_SECRET": tt.secret})
+			if err != nil {
+				t.Fatalf("Load() error = %v", err)
+			}
+
+			err = cfg.RequireVerificationSecret()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("RequireVerificationSecret() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !strings.Contains(err.Error(), "VERIFICATION_SECRET: must be set") {
+				t.Errorf("RequireVerificationSecret() error = %q", err)
+			}
+		})
+	}
+}
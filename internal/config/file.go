@@ -0,0 +1,73 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pelletier/go-toml/v2"
+	"gopkg.in/yaml.v3"
+)
+
+// Reads a flat YAML (.yaml, .yml) or TOML (.toml) config file whose keys are the lowercase environment variable
+// names (e.g. port, postgres_host). Lists may be written as arrays or comma-separated strings.
+// Returns the settings keyed by environment variable name.
+func readFile(path string) (map[string]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read the config file: %w", err)
+	}
+
+	raw := map[string]any{}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &raw)
+	case ".toml":
+		err = toml.Unmarshal(data, &raw)
+	default:
+		return nil, fmt.Errorf("unsupported config file %q (use .yaml, .yml or .toml)", path)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse the config file %q: %w", path, err)
+	}
+
+	settings := make(map[string]string, len(raw))
+
+	for key, value := range raw {
+		text, err := stringify(value)
+		if err != nil {
+			return nil, fmt.Errorf("config file %q: %s: %w", path, key, err)
+		}
+
+		settings[strings.ToUpper(key)] = text
+	}
+
+	return settings, nil
+}
+
+// Converts a decoded scalar or list into its environment variable form.
+func stringify(value any) (string, error) {
+	switch value := value.(type) {
+	case nil:
+		return "", nil
+	case []any:
+		items := make([]string, 0, len(value))
+
+		for _, item := range value {
+			text, err := stringify(item)
+			if err != nil {
+				return "", err
+			}
+			items = append(items, text)
+		}
+
+		return strings.Join(items, ","), nil
+	case map[string]any:
+		return "", fmt.Errorf("nested settings are not supported")
+	default:
+		return fmt.Sprint(value), nil
+	}
+}
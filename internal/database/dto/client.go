@@ -0,0 +1,6 @@
+package dto
+
+// Used to validate and bind the code a client received by SMS to verify its phone.
+type VerifyPhoneDTO struct {
+	Code string `json:"code" binding:"required,len=6,numeric"` // Verification code sent to the client's phone.
+}
@@ -0,0 +1,68 @@
+package dto
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Event Grid event types handled by the delivery report webhook.
+const (
+	SubscriptionValidationEvent = "Microsoft.EventGrid.SubscriptionValidationEvent"
+	EmailDeliveryReportEvent    = "Microsoft.Communication.EmailDeliveryReportReceived"
+	SMSDeliveryReportEvent      = "Microsoft.Communication.SMSDeliveryReportReceived"
+	SMSReceivedEvent            = "Microsoft.Communication.SMSReceived"
+)
+
+// Event delivered by Azure Event Grid in the Event Grid schema.
+// The data is decoded according to the event type.
+type EventGridEvent struct {
+	ID        string          `json:"id" binding:"required"`        // Unique ID of the event.
+	EventType string          `json:"eventType" binding:"required"` // Type of the event, e.g. EmailDeliveryReportEvent.
+	Subject   string          `json:"subject"`                      // Resource path the event relates to.
+	EventTime time.Time       `json:"eventTime"`                    // Time the event was published.
+	Data      json.RawMessage `json:"data"`                         // Event-specific payload.
+}
+
+// Data of the event Event Grid sends when a webhook subscription is created.
+// The validation code must be echoed back to prove the endpoint accepts the subscription.
+type SubscriptionValidationData struct {
+	ValidationCode string `json:"validationCode"` // Code to return in the validation response.
+	ValidationURL  string `json:"validationUrl"`  // Alternative URL to validate the subscription manually.
+}
+
+// Response to a subscription validation event.
+type SubscriptionValidationResponse struct {
+	ValidationResponse string `json:"validationResponse"` // Validation code received in the event.
+}
+
+// Data of an EmailDeliveryReportReceived event.
+type EmailDeliveryReport struct {
+	Sender                string                `json:"sender"`                // Sender email address.
+	Recipient             string                `json:"recipient"`             // Recipient email address.
+	MessageID             string                `json:"messageId"`             // ID of the send operation.
+	Status                string                `json:"status"`                // Delivery status (e.g. Delivered, Bounced, Suppressed, Failed).
+	DeliveryStatusDetails DeliveryStatusDetails `json:"deliveryStatusDetails"` // Details of the delivery status.
+}
+
+// Data of an SMSDeliveryReportReceived event.
+type SMSDeliveryReport struct {
+	MessageID             string `json:"messageId"`             // ID of the message.
+	From                  string `json:"from"`                  // Phone number of the sender.
+	To                    string `json:"to"`                    // Phone number of the recipient.
+	DeliveryStatus        string `json:"deliveryStatus"`        // Delivery status (e.g. Delivered, Failed).
+	DeliveryStatusDetails string `json:"deliveryStatusDetails"` // Details of the delivery status.
+}
+
+// Data of an SMSReceived event, sent when someone replies to the sender number.
+type SMSReceived struct {
+	MessageID         string    `json:"messageId"`         // ID of the inbound message.
+	From              string    `json:"from"`              // Phone number of the sender.
+	To                string    `json:"to"`                // Phone number that received the message (SMS_FROM).
+	Message           string    `json:"message"`           // Text of the message.
+	ReceivedTimestamp time.Time `json:"receivedTimestamp"` // Time the message was received.
+}
+
+// Details of an email delivery status.
+type DeliveryStatusDetails struct {
+	StatusMessage string `json:"statusMessage"` // Human-readable reason of the status.
+}
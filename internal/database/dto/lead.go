@@ -1,12 +1,96 @@
 package dto
 
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"communications/internal/utils"
+)
+
 // Used to validate and bind incoming lead data from the request body.
-// Ensures that required fields are present and conform to basic validation rules before further processing.
+// Binding only ensures that required fields are present; Validate checks the formats and lengths after normalizing.
 type CreateLeadDTO struct {
-	Name    string  `json:"name" binding:"required,min=2,max=31"`         // Name of the user submitting the lead.
-	Phone   string  `json:"phone" binding:"required,min=10,max=15"`       // User's phone number.
-	Email   string  `json:"email" binding:"required,min=5,max=255,email"` // User's email address.
-	Message *string `json:"message" binding:"omitempty,min=2,max=255"`    // Optional message from the user.
+	Name    string  `json:"name" binding:"required"`  // Name of the user submitting the lead.
+	Phone   string  `json:"phone" binding:"required"` // User's phone number, normalized to E.164.
+	Email   string  `json:"email" binding:"required"` // User's email address.
+	Message *string `json:"message"`                  // Optional message from the user.
+
+	UTMSource   *string `json:"utm_source"`   // Optional campaign source (e.g. google, newsletter).
+	UTMMedium   *string `json:"utm_medium"`   // Optional campaign medium (e.g. cpc, email).
+	UTMCampaign *string `json:"utm_campaign"` // Optional campaign name.
+	UTMTerm     *string `json:"utm_term"`     // Optional paid search keyword.
+	UTMContent  *string `json:"utm_content"`  // Optional ad or link variant.
+	Referrer    *string `json:"referrer"`     // Optional URL of the page that referred the user to the website.
+	LandingPage *string `json:"landing_page"` // Optional URL of the page where the user first landed.
+	UserAgent   *string `json:"-"`            // User agent of the submitter, taken from the request headers.
+}
+
+// Runs the full validation pipeline on a bound lead: name normalization, strict email format,
+// phone normalization (using the client's country for national numbers) and message sanitation.
+// Normalizes the fields in place and returns a human-readable error for the first invalid field.
+func (d *CreateLeadDTO) Validate(country string) error {
+	if !utils.ValidateAndNormalizeName(&d.Name) {
+		return fmt.Errorf("name must be %d-%d characters long and contain only letters, numbers, spaces and basic punctuation", utils.MinNameLength, utils.MaxNameLength)
+	}
+
+	d.Email = strings.ToLower(strings.TrimSpace(d.Email))
+	if len(d.Email) > utils.MaxEmailLength || !utils.ValidateEmail(d.Email) {
+		return errors.New("email must be a valid email address")
+	}
+
+	if !utils.NormalizePhoneNumber(&d.Phone, country) {
+		return errors.New("phone number must be in international format or a valid national number for the client's country")
+	}
+
+	if d.Message != nil {
+		message := utils.SanitizeText(*d.Message)
+
+		length := utf8.RuneCountInString(message)
+
+		if length > 0 && (length < utils.MinMessageLength || length > utils.MaxMessageLength) {
+			return fmt.Errorf("message must be %d-%d characters long", utils.MinMessageLength, utils.MaxMessageLength)
+		}
+
+		if message == "" {
+			d.Message = nil
+		} else {
+			d.Message = &message
+		}
+	}
+
+	d.sanitizeTracking()
+
+	return nil
+}
+
+// Sanitizes and length-limits the optional campaign tracking fields.
+// Invalid values are dropped rather than rejected, so tracking data never blocks a lead.
+func (d *CreateLeadDTO) sanitizeTracking() {
+	for _, field := range []**string{&d.UTMSource, &d.UTMMedium, &d.UTMCampaign, &d.UTMTerm, &d.UTMContent} {
+		*field = optional(*field, func(value string) string { return utils.SanitizeLine(value, utils.MaxUTMLength) })
+	}
+
+	for _, field := range []**string{&d.Referrer, &d.LandingPage} {
+		*field = optional(*field, func(value string) string { return utils.SanitizeURL(value, utils.MaxURLLength) })
+	}
+
+	d.UserAgent = optional(d.UserAgent, func(value string) string { return utils.SanitizeLine(value, utils.MaxUserAgent) })
+}
+
+// Applies the sanitizer to an optional value, returning nil if the value is missing or sanitized away.
+func optional(value *string, sanitize func(string) string) *string {
+	if value == nil {
+		return nil
+	}
+
+	sanitized := sanitize(*value)
+	if sanitized == "" {
+		return nil
+	}
+
+	return &sanitized
 }
 
 // Represents a single recipient's email address.
@@ -31,10 +115,10 @@ type EmailContent struct {
 // This structure is used to create a valid payload for Azure's email API.
 // Designed to satisfy Azure's email API requirements.
 type EmailMessage struct {
-	SenderAddress string                  `json:"senderAddress"` // Email address of the sender.
-	Recipients    EmailRecipients         `json:"recipients"`    // List of email recipients.
-	Content       EmailContent            `json:"content"`       // Content of the email.
-	ReplyTo       []EmailRecipientAddress `json:"replyTo"`       // List of reply-to email addresses.
+	SenderAddress string                  `json:"senderAddress"`     // Email address of the sender.
+	Recipients    EmailRecipients         `json:"recipients"`        // List of email recipients.
+	Content       EmailContent            `json:"content"`           // Content of the email.
+	ReplyTo       []EmailRecipientAddress `json:"replyTo,omitempty"` // Optional list of reply-to email addresses.
 }
 
 // This structure is used to create a valid payload for Azure's SMS API.
@@ -44,3 +128,37 @@ type SMSMessage struct {
 	To      []string `json:"to" binding:"required"`      // List of SMS recipients.
 	Message string   `json:"message" binding:"required"` // Textual message content.
 }
+
+// Response of Azure's email API to an accepted message.
+// The ID identifies the send operation and matches the message ID of its delivery reports.
+type EmailSendResult struct {
+	ID     string `json:"id"`     // ID of the send operation.
+	Status string `json:"status"` // Status of the send operation (e.g. Running).
+}
+
+// Response of Azure's SMS API, with one result per recipient.
+type SMSSendResponse struct {
+	Value []SMSSendResult `json:"value"` // Results of the recipients.
+}
+
+// Result of sending an SMS to one recipient.
+// The message ID matches the message ID of its delivery reports.
+type SMSSendResult struct {
+	To           string `json:"to"`                     // Phone number of the recipient.
+	MessageID    string `json:"messageId"`              // ID of the message.
+	Successful   bool   `json:"successful"`             // Indicates if Azure accepted the message.
+	ErrorMessage string `json:"errorMessage,omitempty"` // Reason the message was not accepted.
+}
+
+// Status of an Azure email send operation, returned by the operation status API.
+type EmailOperation struct {
+	ID     string          `json:"id"`              // ID of the send operation.
+	Status string          `json:"status"`          // Status of the operation (NotStarted, Running, Succeeded, Failed, Canceled).
+	Error  *OperationError `json:"error,omitempty"` // Reason the operation failed.
+}
+
+// Error of a failed Azure email send operation.
+type OperationError struct {
+	Code    string `json:"code"`    // Error code.
+	Message string `json:"message"` // Human-readable error message.
+}
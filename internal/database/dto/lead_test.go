@@ -0,0 +1,150 @@
+package dto
+
+import (
+	"strings"
+	"testing"
+)
+
+// Convert a string to a pointer
+func strPtr(s string) *string {
+	return &s
+}
+
+// Checks if the lead validation pipeline normalizes valid leads and rejects invalid ones.
+func TestCreateLeadDTOValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   CreateLeadDTO
+		country string
+		wantErr bool
+		output  CreateLeadDTO
+	}{
+		{
+			"Valid lead is normalized",
+			CreateLeadDTO{Name: "  John   Doe ", Email: " John@Example.com ", Phone: "(555) 123-4567", Message: strPtr("  Hello\x00 there\r\n ")},
+			"US",
+			false,
+			CreateLeadDTO{Name: "John Doe", Email: "john@example.com", Phone: "+15551234567", Message: strPtr("Hello there")},
+		},
+		{
+			"Blank message becomes nil",
+			CreateLeadDTO{Name: "John Doe", Email: "john@example.com", Phone: "+12345678901", Message: strPtr(" \x00\t ")},
+			"",
+			false,
+			CreateLeadDTO{Name: "John Doe", Email: "john@example.com", Phone: "+12345678901"},
+		},
+		{
+			"Missing message stays nil",
+			CreateLeadDTO{Name: "John Doe", Email: "john@example.com", Phone: "0641234567"},
+			"RS",
+			false,
+			CreateLeadDTO{Name: "John Doe", Email: "john@example.com", Phone: "+381641234567"},
+		},
+		{
+			"Padded message within limit",
+			CreateLeadDTO{Name: "John Doe", Email: "john@example.com", Phone: "+12345678901", Message: strPtr("Hi" + strings.Repeat(" ", 300))},
+			"",
+			false,
+			CreateLeadDTO{Name: "John Doe", Email: "john@example.com", Phone: "+12345678901", Message: strPtr("Hi")},
+		},
+		{
+			"Message too short",
+			CreateLeadDTO{Name: "John Doe", Email: "john@example.com", Phone: "+12345678901", Message: strPtr("  a\x00 ")},
+			"",
+			true,
+			CreateLeadDTO{},
+		},
+		{
+			"Message too long",
+			CreateLeadDTO{Name: "John Doe", Email: "john@example.com", Phone: "+12345678901", Message: strPtr(strings.Repeat("a", 256))},
+			"",
+			true,
+			CreateLeadDTO{},
+		},
+		{
+			"Invalid name",
+			CreateLeadDTO{Name: "John@Doe", Email: "john@example.com", Phone: "+12345678901"},
+			"",
+			true,
+			CreateLeadDTO{},
+		},
+		{
+			"Invalid email",
+			CreateLeadDTO{Name: "John Doe", Email: "j@example.com", Phone: "+12345678901"},
+			"",
+			true,
+			CreateLeadDTO{},
+		},
+		{
+			"Invalid phone",
+			CreateLeadDTO{Name: "John Doe", Email: "john@example.com", Phone: "0641234567"},
+			"",
+			true,
+			CreateLeadDTO{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.input
+			err := got.Validate(tt.country)
+
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Validate(%q) error = %v, wantErr %v", tt.country, err, tt.wantErr)
+			}
+
+			if tt.wantErr {
+				return
+			}
+
+			if got.Name != tt.output.Name || got.Email != tt.output.Email || got.Phone != tt.output.Phone {
+				t.Errorf("Validate(%q) normalized to %+v, want %+v", tt.country, got, tt.output)
+			}
+
+			if (got.Message == nil) != (tt.output.Message == nil) || (got.Message != nil && *got.Message != *tt.output.Message) {
+				t.Errorf("Validate(%q) normalized message to %v, want %v", tt.country, got.Message, tt.output.Message)
+			}
+		})
+	}
+}
+
+// Checks if the campaign tracking fields are sanitized, length-limited or dropped.
+func TestCreateLeadDTOValidateTracking(t *testing.T) {
+	body := CreateLeadDTO{
+		Name:        "John Doe",
+		Email:       "john@example.com",
+		Phone:       "+12345678901",
+		UTMSource:   strPtr("  google\n"),
+		UTMMedium:   strPtr(" \x00 "),
+		UTMCampaign: strPtr(strings.Repeat("a", 200)),
+		Referrer:    strPtr("javascript:alert(1)"),
+		LandingPage: strPtr("https://example.com/contact?utm_source=google"),
+		UserAgent:   strPtr("Mozilla/5.0\r\n(X11; Linux)"),
+	}
+
+	if err := body.Validate(""); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+
+	if body.UTMSource == nil || *body.UTMSource != "google" {
+		t.Errorf("UTMSource = %v, want %q", body.UTMSource, "google")
+	}
+	if body.UTMMedium != nil {
+		t.Errorf("UTMMedium = %q, want nil", *body.UTMMedium)
+	}
+	if body.UTMCampaign == nil || len(*body.UTMCampaign) != 127 {
+		t.Errorf("UTMCampaign was not truncated to 127 characters")
+	}
+	if body.UTMTerm != nil || body.UTMContent != nil {
+		t.Errorf("missing UTMTerm and UTMContent should stay nil")
+	}
+	if body.Referrer != nil {
+		t.Errorf("Referrer = %q, want nil", *body.Referrer)
+	}
+	if body.LandingPage == nil || *body.LandingPage != "https://example.com/contact?utm_source=google" {
+		t.Errorf("LandingPage = %v, want the original URL", body.LandingPage)
+	}
+	if body.UserAgent == nil || *body.UserAgent != "Mozilla/5.0 (X11; Linux)" {
+		t.Errorf("UserAgent = %v, want %q", body.UserAgent, "Mozilla/5.0 (X11; Linux)")
+	}
+}
@@ -0,0 +1,44 @@
+package dto
+
+import (
+	"errors"
+	"strings"
+
+	"communications/internal/utils"
+)
+
+// Maximum length of a suppression reason, matching the "reason" column.
+const maxSuppressionReason = 255
+
+// Used to validate and bind a recipient suppressed through the admin API.
+type SuppressionDTO struct {
+	Channel   string  `json:"channel" binding:"required,oneof=email sms"` // Channel of the recipient.
+	Recipient string  `json:"recipient" binding:"required,max=255"`       // Email address or phone number in international format.
+	Reason    *string `json:"reason" binding:"omitempty,max=255"`         // Optional reason of the suppression.
+}
+
+// Normalizes the recipient like the client contacts it is matched against and sanitizes the reason.
+// Returns a human-readable error if the recipient is not valid for the channel.
+func (d *SuppressionDTO) Validate() error {
+	if !NormalizeRecipient(d.Channel, &d.Recipient) {
+		return errors.New("recipient must be a valid email address or a phone number in international format")
+	}
+
+	d.Reason = optional(d.Reason, func(value string) string { return utils.SanitizeLine(value, maxSuppressionReason) })
+
+	return nil
+}
+
+// Normalizes an email address to lowercase or a phone number to E.164, so suppressions match however
+// the address was written. Returns false if the recipient is not valid for the channel.
+func NormalizeRecipient(channel string, recipient *string) bool {
+	switch channel {
+	case "email":
+		*recipient = strings.ToLower(strings.TrimSpace(*recipient))
+		return len(*recipient) <= utils.MaxEmailLength && utils.ValidateEmail(*recipient)
+	case "sms":
+		return utils.NormalizePhoneNumber(recipient, "")
+	default:
+		return false
+	}
+}
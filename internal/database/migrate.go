@@ -0,0 +1,120 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"strconv"
+	"strings"
+
+	"communications/internal/config"
+	"communications/migrations"
+
+	"github.com/golang-migrate/migrate/v4"
+	_ "github.com/golang-migrate/migrate/v4/database/postgres"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Key of the Postgres advisory lock held while migrating.
+// Keeps replicas starting at the same time (and the migrate command) from running migrations concurrently.
+const migrationLockID = 7_263_041_110
+
+// Creates a migration runner for the SQL files embedded in the binary.
+// Used by Migrate at startup and by the migrate command (up, down, version, force). The caller must close it.
+func NewMigration(cfg *config.Config) (*migrate.Migrate, error) {
+	databaseURL := fmt.Sprintf(
+		"postgres://%s:%s@%s:%d/%s?sslmode=%s",
+		cfg.DatabaseUser,
+		cfg.DatabasePassword,
+		cfg.DatabaseHost,
+		cfg.DatabasePort,
+		cfg.DatabaseName,
+		cfg.DatabaseSSL,
+	)
+
+	return newMigration(databaseURL)
+}
+
+// Creates a migration runner for the embedded SQL files against the given postgres:// URL.
+func newMigration(databaseURL string) (*migrate.Migrate, error) {
+	source, err := iofs.New(migrations.FS, ".")
+	if err != nil {
+		return nil, fmt.Errorf("unable to read the embedded SQL migrations: %w", err)
+	}
+
+	migration, err := migrate.NewWithSourceInstance("iofs", source, databaseURL)
+	if err != nil {
+		return nil, fmt.Errorf("unable to initiate the SQL migrations: %w", err)
+	}
+
+	return migration, nil
+}
+
+// Applies all pending SQL migrations while holding the migration lock.
+// Used at startup when AUTO_MIGRATE is enabled, to ensure the database is up-to-date before serving requests.
+func Migrate(ctx context.Context, cfg *config.Config, pool *pgxpool.Pool) error {
+	return WithMigrationLock(ctx, pool, func() error {
+		migration, err := NewMigration(cfg)
+		if err != nil {
+			return err
+		}
+		defer migration.Close()
+
+		if err := migration.Up(); err != nil && !errors.Is(err, migrate.ErrNoChange) {
+			return fmt.Errorf("unable to run the SQL migrations: %w", err)
+		}
+
+		slog.Info("SQL Migrations applied successfully")
+
+		return nil
+	})
+}
+
+// Runs fn while holding a Postgres advisory lock on a dedicated connection.
+// Waits for other holders (e.g. another replica migrating) until ctx is done.
+func WithMigrationLock(ctx context.Context, pool *pgxpool.Pool, fn func() error) error {
+	conn, err := pool.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("unable to acquire a connection for the migration lock: %w", err)
+	}
+	defer conn.Release()
+
+	if _, err := conn.Exec(ctx, `select pg_advisory_lock($1)`, migrationLockID); err != nil {
+		return fmt.Errorf("unable to take the migration lock: %w", err)
+	}
+
+	defer func() {
+		if _, err := conn.Exec(context.WithoutCancel(ctx), `select pg_advisory_unlock($1)`, migrationLockID); err != nil {
+			slog.Error("Unable to release the migration lock", "error", err)
+		}
+	}()
+
+	return fn()
+}
+
+// Returns the highest version among the embedded migrations.
+// Used by the readiness check to detect a database that has not been migrated yet.
+func LatestVersion() (uint, error) {
+	files, err := fs.Glob(migrations.FS, "*.up.sql")
+	if err != nil {
+		return 0, err
+	}
+
+	var latest uint
+
+	for _, file := range files {
+		prefix, _, _ := strings.Cut(file, "_")
+
+		version, err := strconv.ParseUint(prefix, 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid migration file name %q", file)
+		}
+
+		latest = max(latest, uint(version))
+	}
+
+	return latest, nil
+}
@@ -0,0 +1,32 @@
+package database
+
+import (
+	"io/fs"
+	"strings"
+	"testing"
+
+	"communications/migrations"
+)
+
+// Checks if the embedded migrations are numbered without gaps and every up migration has a down migration.
+func TestEmbeddedMigrations(t *testing.T) {
+	ups, err := fs.Glob(migrations.FS, "*.up.sql")
+	if err != nil || len(ups) == 0 {
+		t.Fatalf("no embedded up migrations: %v", err)
+	}
+
+	for _, up := range ups {
+		down := strings.TrimSuffix(up, ".up.sql") + ".down.sql"
+		if _, err := fs.Stat(migrations.FS, down); err != nil {
+			t.Errorf("%s has no down migration %s", up, down)
+		}
+	}
+
+	latest, err := LatestVersion()
+	if err != nil {
+		t.Fatalf("LatestVersion() error = %v", err)
+	}
+	if latest != uint(len(ups)) {
+		t.Errorf("LatestVersion() = %d, want %d (one migration per version without gaps)", latest, len(ups))
+	}
+}
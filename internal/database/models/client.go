@@ -6,13 +6,21 @@ import "time"
 // This entity is manually added to the database and is used to associate incoming leads with the correct recipient.
 // When a new lead is generated, the app checks for the corresponding client and notifies them (e.g., via email).
 type Client struct {
-	ID        string     `json:"id"`                   // Unique identifier for the client.
-	Name      string     `json:"name"`                 // Name of the client.
-	Email     string     `json:"email"`                // Contact email where this app sends lead notifications.
-	Phone     string     `json:"phone"`                // Contact phone number for receiving lead notifications via SMS.
-	Website   *string    `json:"website,omitempty"`    // Optional website URL.
-	Verified  bool       `json:"verified"`             // Indicates if the client is verified.
-	CreatedAt time.Time  `json:"created_at"`           // Timestamp when the client was created.
-	UpdatedAt time.Time  `json:"updated_at"`           // Timestamp when the client was last updated.
-	DeletedAt *time.Time `json:"deleted_at,omitempty"` // Timestamp when the client was deleted (if applicable).
+	ID                 string     `json:"id" db:"id"`                             // Unique identifier for the client.
+	Name               string     `json:"name" db:"name"`                         // Name of the client.
+	Email              string     `json:"email" db:"email"`                       // Contact email where this app sends lead notifications.
+	Phone              string     `json:"phone" db:"phone"`                       // Contact phone number for receiving lead notifications via SMS.
+	Website            *string    `json:"website,omitempty" db:"website"`         // Optional website URL.
+	Country            *string    `json:"country,omitempty" db:"country"`         // Optional ISO 3166-1 alpha-2 country used to normalize national phone numbers.
+	Verified           bool       `json:"verified" db:"verified"`                 // Indicates if both the email and the phone are verified.
+	EmailVerified      bool       `json:"email_verified" db:"email_verified"`     // Indicates if the client confirmed the email through the verification link.
+	PhoneVerified      bool       `json:"phone_verified" db:"phone_verified"`     // Indicates if the client confirmed the phone with the verification code.
+	PhoneCode          *string    `json:"-" db:"phone_code"`                      // Hash of the pending phone verification code.
+	PhoneCodeExpiresAt *time.Time `json:"-" db:"phone_code_expires_at"`           // Expiry of the pending phone verification code.
+	PhoneCodeAttempts  int        `json:"-" db:"phone_code_attempts"`             // Number of attempts to enter the pending phone verification code.
+	EmailQuota         *int       `json:"email_quota,omitempty" db:"email_quota"` // Optional monthly email quota, overriding the default.
+	SMSQuota           *int       `json:"sms_quota,omitempty" db:"sms_quota"`     // Optional monthly SMS quota, overriding the default.
+	CreatedAt          time.Time  `json:"created_at" db:"created_at"`             // Timestamp when the client was created.
+	UpdatedAt          time.Time  `json:"updated_at" db:"updated_at"`             // Timestamp when the client was last updated.
+	DeletedAt          *time.Time `json:"deleted_at,omitempty" db:"deleted_at"`   // Timestamp when the client was deleted (if applicable).
 }
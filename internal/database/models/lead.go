@@ -6,11 +6,23 @@ import "time"
 // Each lead is associated with a client and contains information from the user's submission (e.g., Contact Us form).
 // Used to track and retrieve all leads for a specific client.
 type Lead struct {
-	ID       int       `json:"id"`               // Unique identifier for the lead.
-	Datetime time.Time `json:"datetime"`         // Timestamp when the lead was created.
-	Name     *string   `json:"name,omitempty"`   // Name entered by the user (optional).
-	Email    *string   `json:"email,omitempty"`  // Email entered by the user (optional).
-	Phone    *string   `json:"phone,omitempty"`  // Phone entered by the user (optional).
-	ClientID string    `json:"client_id"`        // Associated client ID.
-	Client   *Client   `json:"client,omitempty"` // Optional client details.
+	ID           int       `json:"id" db:"id"`                                 // Unique identifier for the lead.
+	Datetime     time.Time `json:"datetime" db:"datetime"`                     // Timestamp when the lead was created.
+	Name         string    `json:"name" db:"name"`                             // Name entered by the user.
+	Email        string    `json:"email" db:"email"`                           // Email entered by the user.
+	Phone        string    `json:"phone" db:"phone"`                           // Phone entered by the user, normalized to E.164.
+	ClientID     string    `json:"client_id" db:"client_id"`                   // Associated client ID.
+	EmailVerdict *string   `json:"email_verdict,omitempty" db:"email_verdict"` // Deliverability verdict of the email (if checked).
+	UTMSource    *string   `json:"utm_source,omitempty" db:"utm_source"`       // Campaign source of the submission (if tracked).
+	UTMMedium    *string   `json:"utm_medium,omitempty" db:"utm_medium"`       // Campaign medium of the submission (if tracked).
+	UTMCampaign  *string   `json:"utm_campaign,omitempty" db:"utm_campaign"`   // Campaign name of the submission (if tracked).
+	UTMTerm      *string   `json:"utm_term,omitempty" db:"utm_term"`           // Paid search keyword of the submission (if tracked).
+	UTMContent   *string   `json:"utm_content,omitempty" db:"utm_content"`     // Ad or link variant of the submission (if tracked).
+	Referrer     *string   `json:"referrer,omitempty" db:"referrer"`           // Page that referred the user to the website (if known).
+	LandingPage  *string   `json:"landing_page,omitempty" db:"landing_page"`   // Page where the user first landed (if known).
+	UserAgent    *string   `json:"user_agent,omitempty" db:"user_agent"`       // User agent of the submitter.
+	IPAddress    *string   `json:"ip_address,omitempty" db:"ip_address"`       // IP address of the submitter (truncated if anonymized).
+	Country      *string   `json:"country,omitempty" db:"country"`             // Country of the submitter resolved from the IP address.
+	City         *string   `json:"city,omitempty" db:"city"`                   // City of the submitter resolved from the IP address.
+	Client       *Client   `json:"client,omitempty" db:"-"`                    // Optional client details.
 }
@@ -0,0 +1,30 @@
+package models
+
+import "time"
+
+// Delivery statuses of a notification.
+const (
+	NotificationAccepted   = "accepted"   // Azure accepted the notification; no delivery report yet.
+	NotificationDelivered  = "delivered"  // The notification reached the recipient.
+	NotificationBounced    = "bounced"    // The recipient's mail server rejected the email.
+	NotificationFailed     = "failed"     // The notification could not be delivered (e.g. suppressed, filtered or unreachable).
+	NotificationSuppressed = "suppressed" // The notification was not sent because the recipient is on the suppression list.
+)
+
+// Represents an email or SMS sent to a client about a lead, identified by the provider's message ID.
+// Created when Azure accepts the message (or without a message ID when the recipient is suppressed), and updated by the email operation poller and by the delivery reports
+// received through Event Grid.
+type Notification struct {
+	ID              int        `json:"id" db:"id"`                                       // Unique identifier for the notification.
+	ClientID        string     `json:"client_id" db:"client_id"`                         // Associated client ID.
+	LeadID          *int       `json:"lead_id,omitempty" db:"lead_id"`                   // Associated lead ID (unset if the lead could not be stored).
+	Channel         string     `json:"channel" db:"channel"`                             // Channel of the notification (email, sms).
+	MessageID       *string    `json:"message_id,omitempty" db:"message_id"`             // Operation ID (email) or message ID (SMS) returned by Azure (unset if not sent).
+	Recipient       string     `json:"recipient" db:"recipient"`                         // Email address or phone number the notification was sent to.
+	Status          string     `json:"status" db:"status"`                               // Delivery status (accepted, delivered, bounced, failed, suppressed).
+	Reason          *string    `json:"status_reason,omitempty" db:"status_reason"`       // Provider status and details of the last delivery report.
+	OperationStatus *string    `json:"operation_status,omitempty" db:"operation_status"` // Final status of the Azure email send operation (unset while pending and for SMS).
+	CheckedAt       *time.Time `json:"checked_at,omitempty" db:"checked_at"`             // Timestamp of the last poll of the Azure email operation.
+	CreatedAt       time.Time  `json:"created_at" db:"created_at"`                       // Timestamp when Azure accepted the notification.
+	UpdatedAt       time.Time  `json:"updated_at" db:"updated_at"`                       // Timestamp of the last status change.
+}
@@ -0,0 +1,17 @@
+package models
+
+import "time"
+
+// Represents an SMS a client sent back to the sender number about a lead.
+// Linked to the lead the client was last notified about by SMS, and optionally forwarded to that lead.
+type Reply struct {
+	ID                 int       `json:"id" db:"id"`                                               // Unique identifier for the reply.
+	ClientID           string    `json:"client_id" db:"client_id"`                                 // Associated client ID.
+	LeadID             int       `json:"lead_id" db:"lead_id"`                                     // Lead the reply is about.
+	MessageID          string    `json:"message_id" db:"message_id"`                               // ID of the inbound SMS assigned by Azure.
+	Sender             string    `json:"sender" db:"sender"`                                       // Phone number of the client, in E.164 format.
+	Message            string    `json:"message" db:"message"`                                     // Text of the reply.
+	ForwardedChannel   *string   `json:"forwarded_channel,omitempty" db:"forwarded_channel"`       // Channel the reply was forwarded to the lead through (email, sms).
+	ForwardedMessageID *string   `json:"forwarded_message_id,omitempty" db:"forwarded_message_id"` // Operation ID (email) or message ID (SMS) of the forwarded reply.
+	ReceivedAt         time.Time `json:"received_at" db:"received_at"`                             // Timestamp when the reply was received.
+}
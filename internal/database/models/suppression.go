@@ -0,0 +1,21 @@
+package models
+
+import "time"
+
+// Sources of a suppression.
+const (
+	SuppressionBounce = "bounce"  // The recipient's mail server hard-bounced an email.
+	SuppressionOptOut = "opt_out" // The recipient opted out of SMS (e.g. replied STOP).
+	SuppressionAdmin  = "admin"   // Added through the admin API.
+)
+
+// Represents an email address or phone number that must no longer receive notifications.
+// Lead notifications to a suppressed recipient are skipped until the suppression is removed.
+type Suppression struct {
+	ID        int       `json:"id" db:"id"`                   // Unique identifier for the suppression.
+	Channel   string    `json:"channel" db:"channel"`         // Channel of the recipient (email, sms).
+	Recipient string    `json:"recipient" db:"recipient"`     // Email address (lowercase) or phone number in E.164 format.
+	Reason    *string   `json:"reason,omitempty" db:"reason"` // Why the recipient was suppressed (e.g. the bounce details).
+	Source    string    `json:"source" db:"source"`           // What suppressed the recipient (bounce, opt_out, admin).
+	CreatedAt time.Time `json:"created_at" db:"created_at"`   // Timestamp when the recipient was suppressed.
+}
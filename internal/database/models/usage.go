@@ -0,0 +1,15 @@
+package models
+
+import "time"
+
+// Represents the number of notifications sent on behalf of a client during a billing period (calendar month).
+// Used to meter usage for billing and to enforce the client's monthly quotas.
+type Usage struct {
+	ClientID   string    `json:"client_id"`             // Associated client ID.
+	ClientName string    `json:"client_name,omitempty"` // Name of the client (in usage reports).
+	Period     time.Time `json:"period"`                // First day of the billing period.
+	Emails     int       `json:"emails"`                // Number of emails sent during the period.
+	SMS        int       `json:"sms"`                   // Number of SMS sent during the period.
+	EmailQuota *int      `json:"email_quota,omitempty"` // Monthly email quota (unlimited if omitted).
+	SMSQuota   *int      `json:"sms_quota,omitempty"`   // Monthly SMS quota (unlimited if omitted).
+}
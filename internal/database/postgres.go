@@ -3,21 +3,17 @@ package database
 import (
 	"context"
 	"fmt"
-	"log"
 	"time"
 
 	"communications/internal/config"
 
-	"github.com/golang-migrate/migrate/v4"
-	_ "github.com/golang-migrate/migrate/v4/database/postgres"
-	_ "github.com/golang-migrate/migrate/v4/source/file"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
-// Initializes a PostgreSQL connection pool, applies all pending SQL migrations, and verifies connectivity.
-// Used at application startup to ensure the database is ready and up-to-date before serving requests.
-// Returns a ready-to-use *pgxpool.Pool for database operations, or terminates the app on failure.
-func Connect(cfg *config.Config) *pgxpool.Pool {
+// Initializes a PostgreSQL connection pool and verifies connectivity.
+// Used at application startup and by the management commands to get a ready database connection.
+// Returns a ready-to-use *pgxpool.Pool for database operations, or an error describing the failed step.
+func Connect(cfg *config.Config) (*pgxpool.Pool, error) {
 
 	connectionString := fmt.Sprintf(
 		"user=%s password=%s host=%s port=%d dbname=%s sslmode=%s",
@@ -35,32 +31,12 @@ func Connect(cfg *config.Config) *pgxpool.Pool {
 	pool, err := pgxpool.New(ctx, connectionString)
 
 	if err != nil {
-		log.Fatalf("Unable to connect to the database: %v", err)
+		return nil, fmt.Errorf("unable to connect to the database: %w", err)
 	}
 	if err := pool.Ping(ctx); err != nil {
-		log.Fatalf("Unable to ping the database: %v", err)
+		pool.Close()
+		return nil, fmt.Errorf("unable to ping the database: %w", err)
 	}
 
-	databaseURL := fmt.Sprintf(
-		"postgres://%s:%s@%s:%d/%s?sslmode=%s",
-		cfg.DatabaseUser,
-		cfg.DatabasePassword,
-		cfg.DatabaseHost,
-		cfg.DatabasePort,
-		cfg.DatabaseName,
-		cfg.DatabaseSSL,
-	)
-
-	migration, err := migrate.New("file://migrations", databaseURL)
-
-	if err != nil {
-		log.Fatalf("Unable to initiate the SQL migrations: %v", err)
-	}
-	if err := migration.Up(); err != nil && err != migrate.ErrNoChange {
-		log.Fatalf("Unable to run the SQL migrations: %v", err)
-	}
-
-	fmt.Println("SQL Migrations applied successfully.")
-
-	return pool
+	return pool, nil
 }
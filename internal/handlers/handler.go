@@ -1,7 +1,10 @@
 package handlers
 
 import (
+	"context"
 	"errors"
+	"log/slog"
+	"net"
 	"net/http"
 	"sync"
 
@@ -9,14 +12,20 @@ import (
 	"github.com/google/uuid"
 
 	"communications/internal/database/dto"
+	"communications/internal/database/models"
+	"communications/internal/logger"
+	"communications/internal/metrics"
+	"communications/internal/ratelimit"
+	"communications/internal/repository"
 	"communications/internal/services"
+	"communications/internal/tracing"
 	"communications/internal/utils"
 )
 
 // Handles GET requests to check the application's health status.
 // Pings the database and returns a success or error response based on the connectivity.
 func (h *Handler) HealthHandler(c *gin.Context) {
-	service := h.newService()
+	service := h.newService(c)
 
 	if err := service.CheckHealth(); err != nil {
 		utils.Reject(c, http.StatusServiceUnavailable, "Database connection failed.")
@@ -28,61 +37,194 @@ func (h *Handler) HealthHandler(c *gin.Context) {
 			Status:    utils.StatusSuccess,
 			Message:   "Database is up and running.",
 			Timestamp: utils.GetCurrentTimestamp(),
+			RequestID: utils.GetRequestID(c),
 		},
 		Data: utils.DefaultResponse{},
 	})
 }
 
+// Handles GET requests to check whether the process is alive.
+// Never touches dependencies, so orchestrators only restart the app when it is truly stuck.
+func (h *Handler) LivenessHandler(c *gin.Context) {
+	c.JSON(http.StatusOK, utils.APIResponse[utils.DefaultResponse]{
+		Meta: utils.Meta{
+			Status:    utils.StatusSuccess,
+			Message:   "Service is alive.",
+			Timestamp: utils.GetCurrentTimestamp(),
+			RequestID: utils.GetRequestID(c),
+		},
+		Data: utils.DefaultResponse{},
+	})
+}
+
+// Handles GET requests to check whether the service is ready to accept leads.
+// Reports the status of each dependency and returns 503 if any critical component is degraded.
+func (h *Handler) ReadinessHandler(c *gin.Context) {
+	service := h.newService(c)
+
+	report, ready := service.CheckReadiness(c.Request.Context())
+
+	code, status, message := http.StatusOK, utils.StatusSuccess, "Service is ready."
+	if !ready {
+		code, status, message = http.StatusServiceUnavailable, utils.StatusError, "Service is not ready."
+	}
+
+	c.JSON(code, utils.APIResponse[map[string]services.ComponentStatus]{
+		Meta: utils.Meta{
+			Status:    status,
+			Message:   message,
+			Timestamp: utils.GetCurrentTimestamp(),
+			RequestID: utils.GetRequestID(c),
+		},
+		Data: report,
+	})
+}
+
 // Handles POST requests for incoming leads.
 // Validates the request body, and triggers Email and SMS notifications to the client.
+// The whole submission is tracked by the lifecycle, so shutdown never closes the database pool while
+// notifications are being sent or the lead is being stored.
 // Returns appropriate success or error responses based on the outcome.
 func (h *Handler) LeadHandler(c *gin.Context) {
-	service := h.newService()
+	defer h.Lifecycle.Track()()
+
+	service := h.newService(c)
 
 	id, err := h.validateID(c)
 	if err != nil {
 		return
 	}
 
-	body, err := h.validateBody(c)
+	client, err := h.findClientByID(c, id)
+	if err != nil {
+		return
+	}
+
+	outcome := metrics.LeadInvalid
+	defer func() { metrics.LeadsTotal.WithLabelValues(id, outcome).Inc() }()
+
+	body, err := h.validateBody(c, client)
 	if err != nil {
 		return
 	}
 
-	client, err := h.findClientByID(c, id)
+	submitterLimit := newLimit(h.Cfg.SubmitterTTL, h.Cfg.SubmitterLimit)
+
+	err = h.checkRateLimit(
+		c,
+		ratelimit.Bucket{Key: "client:" + id, Limit: newLimit(h.Cfg.ClientTTL, h.Cfg.ClientLimit)},
+		ratelimit.Bucket{Key: "email:" + body.Email, Limit: submitterLimit},
+		ratelimit.Bucket{Key: "phone:" + body.Phone, Limit: submitterLimit},
+	)
+	if err != nil {
+		outcome = metrics.LeadRateLimited
+		return
+	}
+
+	verdict, err := h.checkEmail(c, service, &body)
+	if err != nil {
+		return
+	}
+
+	skipEmail, skipSMS, err := h.checkVerification(c, service, client)
 	if err != nil {
+		outcome = metrics.LeadUnverified
 		return
 	}
 
-	emailError, smsError := h.sendNotifications(service, client, &body)
-	if emailError != nil && smsError != nil {
+	skipEmail, skipSMS, err = h.reserveQuota(c, service, client, skipEmail, skipSMS)
+	if err != nil {
+		outcome = metrics.LeadFailed
+		if errors.Is(err, services.ErrQuotaExceeded) {
+			outcome = metrics.LeadQuotaExceeded
+		}
+		return
+	}
+
+	email, sms := h.sendNotifications(c, service, client, &body, skipEmail, skipSMS)
+	h.releaseQuota(c, service, client, skipEmail, skipSMS, email, sms)
+
+	switch {
+	case email.err == nil && sms.err == nil:
+		outcome = metrics.LeadSent
+	case email.err == nil || sms.err == nil:
+		outcome = metrics.LeadPartial
+	case isSkipped(email.err) && isSkipped(sms.err):
+		// Suppressed contacts are a deliberate skip, so the lead is kept even though nobody was notified.
+		outcome = metrics.LeadSuppressed
+	default:
+		outcome = metrics.LeadFailed
 		utils.Reject(c, http.StatusInternalServerError, "Failed to send Email and SMS.")
 		return
 	}
 
-	h.Pool.Exec(
-		c.Request.Context(),
-		`insert into "leads" ("name", "email", "phone", "client_id") values ($1, $2, $3, $4)`,
-		body.Name,
-		body.Email,
-		body.Phone,
-		id,
-	)
+	ip, country, city := h.locateSubmitter(c, service)
+
+	lead := models.Lead{
+		Name:         body.Name,
+		Email:        body.Email,
+		Phone:        body.Phone,
+		ClientID:     id,
+		EmailVerdict: (*string)(verdict),
+		UTMSource:    body.UTMSource,
+		UTMMedium:    body.UTMMedium,
+		UTMCampaign:  body.UTMCampaign,
+		UTMTerm:      body.UTMTerm,
+		UTMContent:   body.UTMContent,
+		Referrer:     body.Referrer,
+		LandingPage:  body.LandingPage,
+		UserAgent:    body.UserAgent,
+		IPAddress:    &ip,
+		Country:      country,
+		City:         city,
+	}
+
+	// The lead and its notifications are stored even if the submitter leaves, since they were already sent.
+	ctx, span := tracing.Start(context.WithoutCancel(c.Request.Context()), "insertLead")
+	err = h.Leads.CreateLead(ctx, &lead)
+	tracing.Fail(span, err)
+	span.End()
+	if err != nil {
+		slog.ErrorContext(c.Request.Context(), "Unable to store the lead", "client_id", id, "error", err)
+	}
+
+	h.storeNotifications(c, client, lead.ID, email, sms)
 
 	c.JSON(http.StatusOK, utils.APIResponse[utils.DefaultResponse]{
 		Meta: utils.Meta{
 			Status:    utils.StatusSuccess,
-			Message:   "Email and SMS has been successfully sent to one of the clients.",
+			Message:   leadMessage(email, sms),
 			Timestamp: utils.GetCurrentTimestamp(),
+			RequestID: utils.GetRequestID(c),
 		},
 		Data: utils.DefaultResponse{},
 	})
 }
 
-// Helper to create a new Service instance with the current DB pool and config.
-// Used to provide services with access to environment variables and the database.
-func (h *Handler) newService() *services.Service {
-	return services.NewService(h.Pool, h.Cfg)
+// Helper to create a new Service instance with the handler's repositories and config.
+// Used to provide services with access to environment variables, storage and the request's logger
+// (the default logger outside of requests, e.g. for background workers).
+func (h *Handler) newService(c *gin.Context) *services.Service {
+	service := &services.Service{
+		Cfg:           h.Cfg,
+		Health:        h.Health,
+		Clients:       h.Clients,
+		Leads:         h.Leads,
+		Usage:         h.Usage,
+		Notifications: h.Notifications,
+		Suppressions:  h.Suppressions,
+		Replies:       h.Replies,
+		Resolver:      net.DefaultResolver,
+		GeoIP:         h.GeoIP,
+		Logger:        slog.Default(),
+		HTTPClient:    h.HTTPClient,
+	}
+
+	if c != nil {
+		service.Logger = logger.FromContext(c.Request.Context())
+	}
+
+	return service
 }
 
 // Ensures the id param is a valid UUID to prevent invalid DB queries.
@@ -100,7 +242,8 @@ func (h *Handler) validateID(c *gin.Context) (string, error) {
 
 // Binds and validates incoming lead data to enforce input integrity.
 // Prevents invalid or incomplete data from reaching the notification or DB layers.
-func (h *Handler) validateBody(c *gin.Context) (dto.CreateLeadDTO, error) {
+// The client's country is used to normalize national phone numbers to E.164.
+func (h *Handler) validateBody(c *gin.Context, client *models.Client) (dto.CreateLeadDTO, error) {
 	var body dto.CreateLeadDTO
 
 	if err := c.ShouldBindJSON(&body); err != nil {
@@ -108,55 +251,231 @@ func (h *Handler) validateBody(c *gin.Context) (dto.CreateLeadDTO, error) {
 		return body, err
 	}
 
-	if isValid := utils.ValidatePhoneNumber(body.Phone); !isValid {
-		utils.Reject(c, http.StatusBadRequest, "phone number must start with + and contain at least 10 digits")
-		return body, errors.New("invalid phone")
+	if userAgent := c.Request.UserAgent(); userAgent != "" {
+		body.UserAgent = &userAgent
+	}
+
+	country := ""
+	if client.Country != nil {
+		country = *client.Country
+	}
+
+	if err := body.Validate(country); err != nil {
+		utils.Reject(c, http.StatusBadRequest, err.Error())
+		return body, err
 	}
 
 	return body, nil
 }
 
-// Finds a client by ID in the database and retrieves their email and phone number.
-// Validates client existence and soft-delete status before proceeding with lead logic.
-func (h *Handler) findClientByID(c *gin.Context, id string) (*struct{ Email, Phone string }, error) {
-	var client struct {
-		Email string
-		Phone string
+// Runs the optional deliverability check on the submitter's email, as configured by EMAIL_CHECK.
+// In reject mode, disposable and undeliverable addresses stop the request with a 400 response.
+// Returns the verdict to record on the lead, or nil when the check is disabled.
+func (h *Handler) checkEmail(c *gin.Context, service *services.Service, body *dto.CreateLeadDTO) (*services.EmailVerdict, error) {
+	if h.Cfg.EmailCheck != services.EmailCheckFlag && h.Cfg.EmailCheck != services.EmailCheckReject {
+		return nil, nil
 	}
 
-	row := h.Pool.QueryRow(
-		c.Request.Context(),
-		`select "email", "phone" from "clients" where "id" = $1 and "deleted_at" is null`,
-		id,
-	)
+	verdict := service.CheckEmailDeliverability(c.Request.Context(), body.Email)
+
+	if h.Cfg.EmailCheck == services.EmailCheckReject {
+		switch verdict {
+		case services.EmailVerdictDisposable:
+			utils.Reject(c, http.StatusBadRequest, "disposable email addresses are not allowed")
+			return nil, errors.New("disposable email")
+		case services.EmailVerdictUndeliverable:
+			utils.Reject(c, http.StatusBadRequest, "email address cannot receive mail")
+			return nil, errors.New("undeliverable email")
+		}
+	}
+
+	return &verdict, nil
+}
+
+// Resolves the submitter's IP (respecting TRUSTED_PROXIES) and its location from the optional GeoIP database.
+// The location is looked up before the IP is truncated, so ANONYMIZE_IP does not reduce its accuracy.
+func (h *Handler) locateSubmitter(c *gin.Context, service *services.Service) (ip string, country, city *string) {
+	ip = c.ClientIP()
+	country, city = service.GeoIP.Locate(ip)
+
+	if h.Cfg.AnonymizeIP {
+		ip = utils.AnonymizeIP(ip)
+	}
+
+	return ip, country, city
+}
 
-	if err := row.Scan(&client.Email, &client.Phone); err != nil {
+// Finds a client by ID and retrieves their contact details, country and quotas.
+// Validates client existence and soft-delete status before proceeding with lead logic.
+func (h *Handler) findClientByID(c *gin.Context, id string) (*models.Client, error) {
+	ctx, span := tracing.Start(c.Request.Context(), "findClientByID")
+	defer span.End()
+
+	client, err := h.Clients.FindClient(ctx, id)
+	if errors.Is(err, repository.ErrNotFound) {
 		utils.Reject(c, http.StatusNotFound, "Client not found.")
 		return nil, err
 	}
+	if err != nil {
+		tracing.Fail(span, err)
+		slog.ErrorContext(ctx, "Unable to load the client", "client_id", id, "error", err)
+		utils.Reject(c, http.StatusInternalServerError, "Failed to load the client.")
+		return nil, err
+	}
 
 	return &client, nil
 }
 
+// Result of sending a lead notification through one channel.
+type delivery struct {
+	messageID      string // ID returned by Azure, used to match delivery reports.
+	notificationID int    // ID of the stored notification, 0 if it could not be stored.
+	err            error  // Send error, or the reason the channel was skipped.
+}
+
 // Sends email and SMS concurrently to reduce total response time and improve user experience.
-// Captures and returns both errors to allow the caller to handle partial or complete notification failures.
-func (h *Handler) sendNotifications(service *services.Service, client *struct{ Email, Phone string }, body *dto.CreateLeadDTO) (error, error) {
-	var emailError, smsError error
+// Channels with a skip reason (services.ErrUnverified or services.ErrQuotaExceeded) are not sent and report that reason,
+// and suppressed recipients report services.ErrSuppressed.
+// The sends keep the request's values (request ID, trace) but not its cancellation, so a submitter closing
+// the page does not abort notifications; PROVIDER_TIMEOUT bounds them instead.
+// Each sent notification is stored as soon as Azure accepts it, before the lead, so delivery reports can find it.
+// Captures and returns both results to allow the caller to handle partial or complete notification failures.
+func (h *Handler) sendNotifications(c *gin.Context, service *services.Service, client *models.Client, body *dto.CreateLeadDTO, skipEmail, skipSMS error) (email, sms delivery) {
+	email.err, sms.err = skipEmail, skipSMS
+	ctx := context.WithoutCancel(c.Request.Context())
 	var wg sync.WaitGroup
 
-	wg.Add(2)
+	if skipEmail == nil {
+		wg.Add(1)
 
-	go func() {
-		defer wg.Done()
-		emailError = service.SendEmail(&client.Email, body)
-	}()
+		go func() {
+			defer wg.Done()
+			email.messageID, email.err = service.SendEmail(ctx, &client.Email, body)
+			if email.err == nil {
+				email.notificationID = h.storeNotification(ctx, client, "email", client.Email, email.messageID)
+			}
+		}()
+	}
+
+	if skipSMS == nil {
+		wg.Add(1)
 
-	go func() {
-		defer wg.Done()
-		smsError = service.SendSMS(&client.Phone, body)
-	}()
+		go func() {
+			defer wg.Done()
+			sms.messageID, sms.err = service.SendSMS(ctx, &client.Phone, body)
+			if sms.err == nil {
+				sms.notificationID = h.storeNotification(ctx, client, "sms", client.Phone, sms.messageID)
+			}
+		}()
+	}
 
 	wg.Wait()
 
-	return emailError, smsError
+	observeNotification("email", email.err)
+	observeNotification("sms", sms.err)
+
+	return email, sms
+}
+
+// Reports whether a channel was deliberately not sent (suppressed recipient, unverified contact or exhausted quota)
+// rather than failed.
+func isSkipped(err error) bool {
+	return errors.Is(err, services.ErrSuppressed) || errors.Is(err, services.ErrUnverified) || errors.Is(err, services.ErrQuotaExceeded)
+}
+
+// Describes which notifications were sent for a stored lead.
+func leadMessage(email, sms delivery) string {
+	switch {
+	case email.err == nil && sms.err == nil:
+		return "Email and SMS has been successfully sent to one of the clients."
+	case email.err == nil:
+		return "Email has been successfully sent to one of the clients."
+	case sms.err == nil:
+		return "SMS has been successfully sent to one of the clients."
+	default:
+		return "Lead has been received, but no notification was sent."
+	}
+}
+
+// Records the result of a lead notification: suppressed recipients get their own result, and channels
+// skipped for an exhausted quota or an unverified contact are recorded as skipped.
+func observeNotification(channel string, err error) {
+	if errors.Is(err, services.ErrSuppressed) {
+		metrics.ObserveSuppressed(channel)
+		return
+	}
+
+	metrics.ObserveNotification(channel, err, services.ErrQuotaExceeded, services.ErrUnverified)
+}
+
+// Stores a sent notification with its Azure message ID, so delivery reports can update its status,
+// and returns its ID (0 if it could not be stored). The notification is linked to its lead once the lead is stored.
+// Failures are logged but never fail the lead, since the notification was already sent.
+func (h *Handler) storeNotification(ctx context.Context, client *models.Client, channel, recipient, messageID string) int {
+	notification := models.Notification{
+		ClientID:  client.ID,
+		Channel:   channel,
+		MessageID: &messageID,
+		Recipient: recipient,
+	}
+
+	if err := h.Notifications.CreateNotification(ctx, &notification); err != nil {
+		slog.ErrorContext(ctx, "Unable to store the notification", "client_id", client.ID, "channel", channel, "error", err)
+		return 0
+	}
+
+	return notification.ID
+}
+
+// Links the sent notifications to the stored lead, and stores the notifications skipped for a suppressed
+// recipient with the suppressed status and no message ID.
+// A lead ID of 0 (the lead could not be stored) leaves the notifications unlinked.
+// Failures are logged but never fail the lead, since the notifications were already sent.
+// Uses a context detached from the request, so the rows are stored even if the submitter disconnects.
+func (h *Handler) storeNotifications(c *gin.Context, client *models.Client, leadID int, email, sms delivery) {
+	ctx := context.WithoutCancel(c.Request.Context())
+
+	var lead *int
+	if leadID != 0 {
+		lead = &leadID
+	}
+
+	sent := []struct {
+		channel   string
+		recipient string
+		delivery  delivery
+	}{
+		{"email", client.Email, email},
+		{"sms", client.Phone, sms},
+	}
+
+	var linked []int
+	for _, notification := range sent {
+		if notification.delivery.notificationID != 0 {
+			linked = append(linked, notification.delivery.notificationID)
+		}
+		if !errors.Is(notification.delivery.err, services.ErrSuppressed) {
+			continue
+		}
+
+		suppressed := models.Notification{
+			ClientID:  client.ID,
+			LeadID:    lead,
+			Channel:   notification.channel,
+			Recipient: notification.recipient,
+			Status:    models.NotificationSuppressed,
+		}
+
+		if err := h.Notifications.CreateNotification(ctx, &suppressed); err != nil {
+			slog.ErrorContext(ctx, "Unable to store the notification", "client_id", client.ID, "channel", notification.channel, "error", err)
+		}
+	}
+
+	if lead == nil || len(linked) == 0 {
+		return
+	}
+
+	if err := h.Notifications.LinkNotifications(ctx, leadID, linked); err != nil {
+		slog.ErrorContext(ctx, "Unable to link the notifications to the lead", "client_id", client.ID, "lead_id", leadID, "error", err)
+	}
 }
@@ -0,0 +1,239 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"communications/internal/acstest"
+	"communications/internal/config"
+	"communications/internal/database/models"
+	"communications/internal/repository"
+	"communications/internal/server"
+	"communications/internal/utils"
+)
+
+// ID of the client stored by newTestRouter.
+const testClientID = "6f1c2b9e-3d4a-4f5b-8c6d-7e8f9a0b1c2d"
+
+// Valid lead body, with a national phone number normalized using the client's country.
+const testLead = `{"name": "John Doe", "email": "John@Example.com", "phone": "(202) 555-0123", "message": "Hello there"}`
+
+// Silences the request logs of the handlers under test.
+func TestMain(m *testing.M) {
+	slog.SetDefault(slog.New(slog.NewTextHandler(io.Discard, nil)))
+	os.Exit(m.Run())
+}
+
+// Boots the router with in-memory repositories holding one verified client and a fake Azure Communication Services.
+// configure may adjust the config and the client before the router is created.
+func newTestRouter(t *testing.T, configure func(cfg *config.Config, client *models.Client)) (*gin.Engine, *repository.Memory, *acstest.Server) {
+	t.Helper()
+
+	azure := acstest.NewServer(t)
+	store := repository.NewMemory()
+	country := "US"
+
+	client := &models.Client{
+		ID:            testClientID,
+		Name:          "Acme",
+		Email:         "client@example.com",
+		Phone:         "+12025550199",
+		Country:       &country,
+		Verified:      true,
+		EmailVerified: true,
+		PhoneVerified: true,
+	}
+
+	cfg := &config.Config{
+		GinMode:            "test",
+		ThrottleTTL:        60,
+		ThrottleLimit:      1000,
+		RateLimiter:        "memory",
+		AllowedOrigins:     []string{"http://localhost:3000"},
+		AzureURL:           azure.ConnectionString(),
+		ProviderTimeout:    1,
+		EmailFrom:          "noreply@example.com",
+		SMSFrom:            "+12025550100",
+		QuotaMode:          "degrade",
+		EmailCheck:         "off",
+		PublicURL:          "http://localhost:5000",
+		VerificationSecret: "signing-key",
+		LinkTTL:            3600,
+		CodeTTL:            900,
+		UnverifiedMode:     "block",
+		ReplyForwarding:    "off",
+		ReplyWindow:        3600,
+		SMSHelpMessage:     "Lead notifications. Reply STOP to unsubscribe.",
+	}
+
+	if configure != nil {
+		configure(cfg, client)
+	}
+
+	store.CreateClient(context.Background(), client)
+
+	lifecycle := server.NewLifecycle()
+	t.Cleanup(func() { lifecycle.Shutdown(context.Background()) })
+
+	router, err := Init(cfg, nil, store, lifecycle)
+	if err != nil {
+		t.Fatalf("Init() error = %v", err)
+	}
+
+	return router, store, azure
+}
+
+// Checks the outcome of lead submissions: the response, the provider calls, the stored lead and the usage.
+func TestLeadHandler(t *testing.T) {
+	tests := []struct {
+		name      string
+		id        string
+		body      string
+		setup     func(azure *acstest.Server)
+		status    int
+		message   string
+		emails    int // Email requests received by the provider.
+		sms       int // SMS requests received by the provider.
+		leads     int // Leads stored.
+		usedEmail int // Emails counted towards the quota.
+		usedSMS   int // SMS counted towards the quota.
+	}{
+		{"Success", testClientID, testLead, nil, http.StatusOK, "successfully sent", 1, 1, 1, 1, 1},
+		{"SMS fails", testClientID, testLead, func(azure *acstest.Server) {
+			azure.Fail(acstest.SMS, http.StatusInternalServerError)
+		}, http.StatusOK, "successfully sent", 1, 1, 1, 1, 0},
+		{"Email fails", testClientID, testLead, func(azure *acstest.Server) {
+			azure.Fail(acstest.Email, http.StatusBadRequest)
+		}, http.StatusOK, "successfully sent", 1, 1, 1, 0, 1},
+		{"Both fail", testClientID, testLead, func(azure *acstest.Server) {
+			azure.Fail(acstest.Email, http.StatusInternalServerError)
+			azure.Fail(acstest.SMS, http.StatusServiceUnavailable)
+		}, http.StatusInternalServerError, "Failed to send Email and SMS.", 1, 1, 0, 0, 0},
+		{"SMS times out", testClientID, testLead, func(azure *acstest.Server) {
+			azure.Respond(acstest.SMS, acstest.Response{Delay: 3 * time.Second})
+		}, http.StatusOK, "successfully sent", 1, 1, 1, 1, 0},
+		{"Unknown client", "00000000-0000-4000-8000-000000000000", testLead, nil, http.StatusNotFound, "Client not found.", 0, 0, 0, 0, 0},
+		{"Invalid client ID", "acme", testLead, nil, http.StatusBadRequest, "id must be a UUID", 0, 0, 0, 0, 0},
+		{"Padded fields", testClientID, `{"name": "  John      Doe           Smith      ", "email": " John@Example.com ", "phone": " +1 202 555 0123 ", "message": "  Hi  "}`, nil, http.StatusOK, "successfully sent", 1, 1, 1, 1, 1},
+		{"Name too long", testClientID, `{"name": "Johnathan Alexander Doe-Smithson", "email": "john@example.com", "phone": "+12025550123"}`, nil, http.StatusBadRequest, "name must be", 0, 0, 0, 0, 0},
+		{"Missing name", testClientID, `{"email": "john@example.com", "phone": "+12025550123"}`, nil, http.StatusBadRequest, "Name", 0, 0, 0, 0, 0},
+		{"Invalid email", testClientID, `{"name": "John Doe", "email": "john@", "phone": "+12025550123"}`, nil, http.StatusBadRequest, "email", 0, 0, 0, 0, 0},
+		{"Invalid phone", testClientID, `{"name": "John Doe", "email": "john@example.com", "phone": "555-CALL-NOW"}`, nil, http.StatusBadRequest, "phone", 0, 0, 0, 0, 0},
+		{"Malformed JSON", testClientID, `{"name": `, nil, http.StatusBadRequest, "", 0, 0, 0, 0, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router, store, azure := newTestRouter(t, nil)
+			if tt.setup != nil {
+				tt.setup(azure)
+			}
+
+			response := postLead(router, tt.id, tt.body)
+
+			if response.Code != tt.status {
+				t.Fatalf("status = %d, want %d (body %s)", response.Code, tt.status, response.Body)
+			}
+
+			var body utils.APIResponse[utils.DefaultResponse]
+			if err := json.Unmarshal(response.Body.Bytes(), &body); err != nil {
+				t.Fatalf("invalid response body %s: %v", response.Body, err)
+			}
+			if !strings.Contains(body.Meta.Message, tt.message) {
+				t.Errorf("message = %q, want it to contain %q", body.Meta.Message, tt.message)
+			}
+
+			if got := len(azure.Requests(acstest.Email)); got != tt.emails {
+				t.Errorf("email requests = %d, want %d", got, tt.emails)
+			}
+			if got := len(azure.Requests(acstest.SMS)); got != tt.sms {
+				t.Errorf("SMS requests = %d, want %d", got, tt.sms)
+			}
+			if got := len(store.Leads()); got != tt.leads {
+				t.Errorf("stored leads = %d, want %d", got, tt.leads)
+			}
+
+			usage, _ := store.CurrentUsage(context.Background(), testClientID)
+			if usage.Emails != tt.usedEmail || usage.SMS != tt.usedSMS {
+				t.Errorf("usage = %d emails, %d SMS, want %d and %d", usage.Emails, usage.SMS, tt.usedEmail, tt.usedSMS)
+			}
+		})
+	}
+}
+
+// Checks if the notifications go to the client's contacts and the lead is stored normalized.
+func TestLeadHandlerPayloads(t *testing.T) {
+	router, store, azure := newTestRouter(t, nil)
+
+	if response := postLead(router, testClientID, testLead); response.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200 (body %s)", response.Code, response.Body)
+	}
+
+	email := azure.Requests(acstest.Email)[0].Email
+	if email.Recipients.To[0].Address != "client@example.com" || email.ReplyTo[0].Address != "john@example.com" {
+		t.Errorf("email sent to %v with reply-to %v", email.Recipients.To, email.ReplyTo)
+	}
+	if email.SenderAddress != "noreply@example.com" || !strings.Contains(email.Content.HTML, "Hello there") {
+		t.Errorf("email from %q with body %q", email.SenderAddress, email.Content.HTML)
+	}
+
+	sms := azure.Requests(acstest.SMS)[0].SMS
+	if sms.From != "+12025550100" || len(sms.To) != 1 || sms.To[0] != "+12025550199" {
+		t.Errorf("SMS sent from %q to %v", sms.From, sms.To)
+	}
+	if !strings.Contains(sms.Message, "+12025550123") {
+		t.Errorf("SMS message %q does not contain the normalized phone", sms.Message)
+	}
+
+	lead := store.Leads()[0]
+	if lead.ClientID != testClientID || lead.Email != "john@example.com" || lead.Phone != "+12025550123" {
+		t.Errorf("stored lead = %+v", lead)
+	}
+}
+
+// Checks if leads stay within the client's quota and are metered even if the submitter leaves before the response.
+func TestLeadHandlerQuota(t *testing.T) {
+	router, store, azure := newTestRouter(t, func(cfg *config.Config, client *models.Client) {
+		cfg.EmailQuota, cfg.SMSQuota = 1, 1
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	request := httptest.NewRequestWithContext(ctx, http.MethodPost, "/api/v1/leads/"+testClientID, strings.NewReader(testLead))
+	request.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(httptest.NewRecorder(), request)
+
+	usage, _ := store.CurrentUsage(context.Background(), testClientID)
+	if usage.Emails != 1 || usage.SMS != 1 {
+		t.Errorf("usage = %d emails, %d SMS after the submitter left, want 1 and 1", usage.Emails, usage.SMS)
+	}
+
+	if response := postLead(router, testClientID, testLead); response.Code != http.StatusTooManyRequests {
+		t.Errorf("status over the quota = %d, want 429 (body %s)", response.Code, response.Body)
+	}
+	if got := len(azure.Requests("")); got != 2 {
+		t.Errorf("provider requests = %d, want 2", got)
+	}
+}
+
+// Submits a lead for the client with the given ID.
+func postLead(router *gin.Engine, id, body string) *httptest.ResponseRecorder {
+	request := httptest.NewRequest(http.MethodPost, "/api/v1/leads/"+id, strings.NewReader(body))
+	request.Header.Set("Content-Type", "application/json")
+
+	response := httptest.NewRecorder()
+	router.ServeHTTP(response, request)
+
+	return response
+}
@@ -0,0 +1,51 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"communications/internal/config"
+	"communications/internal/database/models"
+)
+
+// Checks if the health endpoints report the status of the injected store and configuration.
+func TestHealthHandlers(t *testing.T) {
+	tests := []struct {
+		name      string
+		path      string
+		configure func(cfg *config.Config, client *models.Client)
+		status    int
+	}{
+		{"Health", "/api/v1/health", nil, http.StatusOK},
+		{"Live", "/api/v1/health/live", nil, http.StatusOK},
+		{"Ready", "/api/v1/health/ready", nil, http.StatusOK},
+		{"Ready with Azure's default sender", "/api/v1/health/ready", func(cfg *config.Config, client *models.Client) {
+			cfg.EmailFrom, cfg.SMSFrom = "DoNotReply@0a1b2c3d.azurecomm.net", "Acme"
+		}, http.StatusOK},
+		{"Invalid provider configuration", "/api/v1/health/ready", func(cfg *config.Config, client *models.Client) {
+			cfg.AzureURL = ""
+		}, http.StatusServiceUnavailable},
+		{"Invalid sender", "/api/v1/health/ready", func(cfg *config.Config, client *models.Client) {
+			cfg.SMSFrom = "+1 (202) 555-0100"
+		}, http.StatusServiceUnavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router, _, _ := newTestRouter(t, tt.configure)
+
+			response := httptest.NewRecorder()
+			router.ServeHTTP(response, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+			if response.Code != tt.status {
+				t.Errorf("status = %d, want %d (body %s)", response.Code, tt.status, response.Body)
+			}
+
+			if tt.path == "/api/v1/health/ready" && !strings.Contains(response.Body.String(), `"outbox"`) {
+				t.Errorf("readiness report %s does not include the outbox", response.Body)
+			}
+		})
+	}
+}
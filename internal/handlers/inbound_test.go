@@ -0,0 +1,193 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"communications/internal/acstest"
+	"communications/internal/config"
+	"communications/internal/database/dto"
+	"communications/internal/database/models"
+)
+
+// Phone of the client stored by newTestRouter.
+const testClientPhone = "+12025550199"
+
+// Answers sent after opt-out and opt-in keywords, as asserted by the tests.
+const (
+	optOutAnswer = "You have been unsubscribed and will no longer receive lead notifications. Reply START to resubscribe."
+	optInAnswer  = "You have been resubscribed to lead notifications. Reply STOP to unsubscribe."
+)
+
+// Checks if opt-out, opt-in and help keywords update the suppression list and are answered.
+func TestInboundSMSKeywords(t *testing.T) {
+	tests := []struct {
+		name       string
+		suppressed string   // Source of the client phone's suppression before the message; empty if none.
+		messages   []string // Messages sent by the client, in order.
+		suppress   string   // Expected suppression source afterwards; empty if none.
+		answers    []string // Expected answers, in order.
+	}{
+		{"Stop", "", []string{"STOP"}, models.SuppressionOptOut, []string{optOutAnswer}},
+		{"Stop with punctuation", "", []string{" unsubscribe. "}, models.SuppressionOptOut, []string{optOutAnswer}},
+		{"Stop then start", "", []string{"Stop", "START"}, "", []string{optOutAnswer, optInAnswer}},
+		{"Start without opt-out", "", []string{"start"}, "", []string{optInAnswer}},
+		{"Start keeps admin suppression", models.SuppressionAdmin, []string{"START"}, models.SuppressionAdmin, nil},
+		{"Stop then start keeps admin suppression", models.SuppressionAdmin, []string{"STOP", "START"}, models.SuppressionAdmin, []string{optOutAnswer}},
+		{"Help", "", []string{"help"}, "", []string{"Lead notifications. Reply STOP to unsubscribe."}},
+		{"Stop in a sentence", "", []string{"Please stop calling"}, "", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router, store, azure := newWebhookRouter(t)
+
+			if tt.suppressed != "" {
+				store.AddSuppression(context.Background(), &models.Suppression{Channel: "sms", Recipient: testClientPhone, Source: tt.suppressed})
+			}
+
+			for i, message := range tt.messages {
+				if response := postInboundSMS(router, fmt.Sprintf("inbound-%d", i), testClientPhone, message); response.Code != http.StatusOK {
+					t.Fatalf("status = %d, want 200 (body %s)", response.Code, response.Body)
+				}
+			}
+
+			suppression, err := store.FindSuppression(context.Background(), "sms", testClientPhone)
+			if (tt.suppress == "" && err == nil) || (tt.suppress != "" && (err != nil || suppression.Source != tt.suppress)) {
+				t.Errorf("suppression = %+v, %v, want source %q", suppression, err, tt.suppress)
+			}
+
+			answers := azure.Requests(acstest.SMS)
+			if len(answers) != len(tt.answers) {
+				t.Fatalf("answers = %d, want %d", len(answers), len(tt.answers))
+			}
+			for i, answer := range answers {
+				if answer.SMS.To[0] != testClientPhone || answer.SMS.Message != tt.answers[i] {
+					t.Errorf("answer %d = %+v, want %q to the client", i, answer.SMS, tt.answers[i])
+				}
+			}
+		})
+	}
+}
+
+// Checks if a redelivered keyword SMS is not answered again.
+func TestInboundSMSKeywordsRedelivered(t *testing.T) {
+	router, _, azure := newWebhookRouter(t)
+
+	for _, message := range []string{"STOP", "STOP", "HELP", "HELP"} {
+		if response := postInboundSMS(router, "inbound-"+message, testClientPhone, message); response.Code != http.StatusOK {
+			t.Fatalf("status = %d, want 200 (body %s)", response.Code, response.Body)
+		}
+	}
+
+	if answers := azure.Requests(acstest.SMS); len(answers) != 2 {
+		t.Errorf("answers = %d, want 2", len(answers))
+	}
+}
+
+// Checks if client replies are linked to the lead last notified by SMS and forwarded as configured,
+// within the client's quota and only when the reply matches a single recent lead.
+func TestInboundSMSReplies(t *testing.T) {
+	tests := []struct {
+		name       string
+		forwarding string
+		sender     string
+		leads      int  // Leads notified before the reply.
+		smsQuota   int  // Monthly SMS quota of the client; 0 for unlimited.
+		redeliver  bool // Whether Event Grid delivers the reply twice.
+		suppressed bool // Whether the lead's email is suppressed.
+		replies    int  // Replies stored.
+		emails     int  // Forwarded emails.
+		sms        int  // Forwarded SMS.
+	}{
+		{"Stored only", "off", testClientPhone, 1, 0, false, false, 1, 0, 0},
+		{"Forwarded by email", "email", testClientPhone, 1, 0, false, false, 1, 1, 0},
+		{"Forwarded by SMS", "sms", testClientPhone, 1, 0, false, false, 1, 0, 1},
+		{"Redelivered", "sms", testClientPhone, 1, 0, true, false, 1, 0, 1},
+		{"Lead suppressed", "email", testClientPhone, 1, 0, false, true, 1, 0, 0},
+		{"Unknown sender", "email", "+12025550111", 1, 0, false, false, 0, 0, 0},
+		{"Several recent leads", "email", testClientPhone, 2, 0, false, false, 1, 0, 0},
+		{"Over the SMS quota", "sms", testClientPhone, 1, 1, false, false, 1, 0, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router, store, azure := newTestRouter(t, func(cfg *config.Config, client *models.Client) {
+				cfg.WebhookToken = testWebhookToken
+				cfg.ReplyForwarding = tt.forwarding
+				cfg.SMSQuota = tt.smsQuota
+			})
+
+			for range tt.leads {
+				if response := postLead(router, testClientID, testLead); response.Code != http.StatusOK {
+					t.Fatalf("lead status = %d, want 200 (body %s)", response.Code, response.Body)
+				}
+			}
+
+			lead := store.Leads()[tt.leads-1]
+			if tt.suppressed {
+				store.AddSuppression(context.Background(), &models.Suppression{Channel: "email", Recipient: lead.Email, Source: models.SuppressionAdmin})
+			}
+
+			deliveries := 1
+			if tt.redeliver {
+				deliveries = 2
+			}
+			for range deliveries {
+				if response := postInboundSMS(router, "inbound-1", tt.sender, "Thanks, I will call you tomorrow.\nBest"); response.Code != http.StatusOK {
+					t.Fatalf("status = %d, want 200 (body %s)", response.Code, response.Body)
+				}
+			}
+
+			replies := store.Replies()
+			if len(replies) != tt.replies {
+				t.Fatalf("stored replies = %+v, want %d", replies, tt.replies)
+			}
+			if tt.replies > 0 && (replies[0].LeadID != lead.ID || replies[0].ClientID != testClientID || replies[0].Sender != testClientPhone) {
+				t.Errorf("reply = %+v, want it linked to lead %d", replies[0], lead.ID)
+			}
+			if tt.replies > 0 && (replies[0].ForwardedChannel != nil) != (tt.emails+tt.sms > 0) {
+				t.Errorf("reply forwarded channel = %v, want forwarded %v", replies[0].ForwardedChannel, tt.emails+tt.sms > 0)
+			}
+
+			emails := azure.Requests(acstest.Email)[tt.leads:]
+			if len(emails) != tt.emails {
+				t.Fatalf("forwarded emails = %d, want %d", len(emails), tt.emails)
+			}
+			for _, email := range emails {
+				if email.Email.Recipients.To[0].Address != lead.Email || email.Email.ReplyTo[0].Address != "client@example.com" {
+					t.Errorf("forwarded email to %+v, reply to %+v", email.Email.Recipients.To, email.Email.ReplyTo)
+				}
+			}
+
+			sms := azure.Requests(acstest.SMS)[tt.leads:]
+			if len(sms) != tt.sms {
+				t.Fatalf("forwarded SMS = %d, want %d", len(sms), tt.sms)
+			}
+			for _, message := range sms {
+				if message.SMS.To[0] != lead.Phone || message.SMS.Message != "Acme: Thanks, I will call you tomorrow.\nBest" {
+					t.Errorf("forwarded SMS = %+v", message.SMS)
+				}
+			}
+		})
+	}
+}
+
+// Posts an SMSReceived event for a message sent by the phone to the sender number.
+func postInboundSMS(router *gin.Engine, messageID, from, message string) *httptest.ResponseRecorder {
+	text, _ := json.Marshal(message)
+
+	return postEvents(router, "?token="+testWebhookToken, `[{
+		"id": "event-`+messageID+`",
+		"eventType": "`+dto.SMSReceivedEvent+`",
+		"subject": "phonenumber/15555555555",
+		"eventTime": "2025-06-15T12:00:00Z",
+		"data": {"messageId": "`+messageID+`", "from": "`+from+`", "to": "+12025550100", "message": `+string(text)+`, "receivedTimestamp": "2025-06-15T12:00:00Z"}
+	}]`)
+}
@@ -0,0 +1,130 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"math"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"communications/internal/config"
+	"communications/internal/metrics"
+	"communications/internal/ratelimit"
+	"communications/internal/server"
+	"communications/internal/utils"
+)
+
+// Creates the rate limiter backend selected by RATE_LIMITER.
+// The Postgres backend shares limits between replicas; its expired buckets are cleaned up every minute
+// by a background worker that stops on shutdown.
+func newRateLimiter(cfg *config.Config, db *pgxpool.Pool, lifecycle *server.Lifecycle) ratelimit.Limiter {
+	if cfg.RateLimiter != "postgres" {
+		return ratelimit.NewMemory()
+	}
+
+	limiter := ratelimit.NewPostgres(db)
+
+	lifecycle.Go(func(ctx context.Context) {
+		ticker := time.NewTicker(time.Minute)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := limiter.Cleanup(ctx); err != nil && ctx.Err() == nil {
+					slog.Error("Unable to clean up the rate limits", "error", err)
+				}
+			}
+		}
+	})
+
+	return limiter
+}
+
+// Routes exempt from the per-IP rate limit. Event Grid delivers every event in its own request from
+// a few Azure addresses, and the webhook is already protected by WEBHOOK_TOKEN.
+var rateLimitExempt = []string{"/api/v1/webhooks/eventgrid"}
+
+// Applies a rate limiter middleware to all requests based on client IP, except for rateLimitExempt routes.
+// Every route has its own budget, so e.g. health checks never consume the budget for leads.
+// Returns HTTP 429 if the client exceeds the allowed request rate.
+func (h *Handler) setRateLimiter() gin.HandlerFunc {
+	limit := newLimit(h.Cfg.ThrottleTTL, h.Cfg.ThrottleLimit)
+
+	return func(c *gin.Context) {
+		if slices.Contains(rateLimitExempt, c.FullPath()) {
+			c.Next()
+			return
+		}
+
+		if err := h.checkRateLimit(c, ratelimit.Bucket{Key: "ip:" + c.FullPath() + ":" + c.ClientIP(), Limit: limit}); err != nil {
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// Consumes one request from the budget of every bucket and rejects the request with 429 if any budget is exhausted.
+// Budgets are only consumed when every bucket allows the request, so a rejection never spends the others.
+// Disabled limits (zero limit) are skipped. If the limiter backend fails, the request is allowed
+// so that an outage never blocks leads.
+func (h *Handler) checkRateLimit(c *gin.Context, buckets ...ratelimit.Bucket) error {
+	buckets = slices.DeleteFunc(buckets, func(bucket ratelimit.Bucket) bool { return bucket.Limit.Burst <= 0 })
+	if len(buckets) == 0 {
+		return nil
+	}
+
+	result, err := h.Limiter.Allow(c.Request.Context(), buckets...)
+	if err != nil {
+		slog.ErrorContext(c.Request.Context(), "Unable to check the rate limit", "error", err)
+		return nil
+	}
+
+	if !result.Allowed {
+		layer, _, _ := strings.Cut(result.Key, ":")
+		metrics.RateLimitRejectionsTotal.WithLabelValues(layer).Inc()
+
+		rejectTooManyRequests(c, result)
+		return errors.New("rate limit exceeded")
+	}
+
+	return nil
+}
+
+// Converts TTL/limit settings into a token bucket limit.
+func newLimit(ttl, limit int) ratelimit.Limit {
+	return ratelimit.Limit{Burst: limit, Interval: time.Duration(ttl) * time.Second}
+}
+
+// Aborts the request with HTTP 429 and the RateLimit-* and Retry-After headers describing the exhausted budget.
+func rejectTooManyRequests(c *gin.Context, result ratelimit.Result) {
+	c.Header("RateLimit-Limit", strconv.Itoa(result.Limit))
+	c.Header("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+	c.Header("RateLimit-Reset", strconv.Itoa(seconds(result.Reset)))
+	c.Header("Retry-After", strconv.Itoa(seconds(result.RetryAfter)))
+
+	c.AbortWithStatusJSON(http.StatusTooManyRequests, utils.APIResponse[utils.DefaultResponse]{
+		Meta: utils.Meta{
+			Status:    utils.StatusError,
+			Message:   "Too many requests. Please try again later.",
+			Timestamp: utils.GetCurrentTimestamp(),
+			RequestID: utils.GetRequestID(c),
+		},
+		Data: utils.DefaultResponse{},
+	})
+}
+
+// Rounds a duration up to whole seconds, as required by the rate limit headers.
+func seconds(duration time.Duration) int {
+	return int(math.Ceil(duration.Seconds()))
+}
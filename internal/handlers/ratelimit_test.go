@@ -0,0 +1,78 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"testing"
+
+	"communications/internal/config"
+	"communications/internal/database/models"
+)
+
+// Lead bodies of other submitters, sharing either the email or the phone of testLead.
+const (
+	otherPhoneLead = `{"name": "John Doe", "email": "john@example.com", "phone": "+12025550124"}`
+	otherEmailLead = `{"name": "Jane Doe", "email": "jane@example.com", "phone": "+12025550123"}`
+	otherLead      = `{"name": "Jane Doe", "email": "jane@example.com", "phone": "+12025550124"}`
+)
+
+// Checks the layered rate limits of leads (IP, client, submitter email and phone) and their response headers.
+// Rejected and invalid leads must never consume the budget of another layer.
+func TestLeadRateLimits(t *testing.T) {
+	tests := []struct {
+		name      string
+		configure func(cfg *config.Config, client *models.Client)
+		bodies    []string // Lead bodies posted in order.
+		statuses  []int    // Expected status of each post.
+	}{
+		{"IP limit", func(cfg *config.Config, client *models.Client) {
+			cfg.ThrottleLimit = 1
+		}, []string{testLead, otherLead}, []int{http.StatusOK, http.StatusTooManyRequests}},
+		{"Client limit", func(cfg *config.Config, client *models.Client) {
+			cfg.ClientTTL, cfg.ClientLimit = 3600, 1
+		}, []string{testLead, otherLead}, []int{http.StatusOK, http.StatusTooManyRequests}},
+		{"Malformed leads keep the client budget", func(cfg *config.Config, client *models.Client) {
+			cfg.ClientTTL, cfg.ClientLimit = 3600, 1
+		}, []string{`{"name": `, `{"name": "John Doe"}`, testLead}, []int{http.StatusBadRequest, http.StatusBadRequest, http.StatusOK}},
+		{"Submitter email limit", func(cfg *config.Config, client *models.Client) {
+			cfg.SubmitterTTL, cfg.SubmitterLimit = 3600, 1
+		}, []string{testLead, otherPhoneLead}, []int{http.StatusOK, http.StatusTooManyRequests}},
+		{"Submitter phone limit", func(cfg *config.Config, client *models.Client) {
+			cfg.SubmitterTTL, cfg.SubmitterLimit = 3600, 1
+		}, []string{testLead, otherEmailLead}, []int{http.StatusOK, http.StatusTooManyRequests}},
+		{"Rejected phone keeps the email budget", func(cfg *config.Config, client *models.Client) {
+			cfg.SubmitterTTL, cfg.SubmitterLimit = 3600, 1
+		}, []string{testLead, otherEmailLead, otherLead}, []int{http.StatusOK, http.StatusTooManyRequests, http.StatusOK}},
+		{"Rejected submitter keeps the client budget", func(cfg *config.Config, client *models.Client) {
+			cfg.ClientTTL, cfg.ClientLimit = 3600, 2
+			cfg.SubmitterTTL, cfg.SubmitterLimit = 3600, 1
+		}, []string{testLead, testLead, otherLead}, []int{http.StatusOK, http.StatusTooManyRequests, http.StatusOK}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router, _, _ := newTestRouter(t, tt.configure)
+
+			for i, body := range tt.bodies {
+				response := postLead(router, testClientID, body)
+				if response.Code != tt.statuses[i] {
+					t.Fatalf("lead %d status = %d, want %d (body %s)", i+1, response.Code, tt.statuses[i], response.Body)
+				}
+
+				if response.Code != http.StatusTooManyRequests {
+					continue
+				}
+
+				header := response.Header()
+				if header.Get("RateLimit-Limit") != "1" || header.Get("RateLimit-Remaining") != "0" {
+					t.Errorf("RateLimit-Limit = %q, RateLimit-Remaining = %q, want 1 and 0", header.Get("RateLimit-Limit"), header.Get("RateLimit-Remaining"))
+				}
+				for _, name := range []string{"RateLimit-Reset", "Retry-After"} {
+					if seconds, err := strconv.Atoi(header.Get(name)); err != nil || seconds <= 0 {
+						t.Errorf("%s = %q, want a positive number of seconds", name, header.Get(name))
+					}
+				}
+			}
+		})
+	}
+}
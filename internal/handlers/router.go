@@ -1,54 +1,293 @@
 package handlers
 
 import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
 	"net/http"
+	"regexp"
+	"runtime/debug"
+	"strings"
 	"time"
 
 	"github.com/gin-contrib/cors"
 	"github.com/gin-contrib/gzip"
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5/pgxpool"
-	"github.com/patrickmn/go-cache"
-	"golang.org/x/time/rate"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
 
 	"communications/internal/config"
+	"communications/internal/logger"
+	"communications/internal/metrics"
+	"communications/internal/ratelimit"
+	"communications/internal/repository"
+	"communications/internal/server"
+	"communications/internal/services"
+	"communications/internal/tracing"
 	"communications/internal/utils"
 )
 
-// Provides access to the database connection pool and configuration.
-// Used to give all route handlers access to .env variables and the DB pool.
+// Accepted format of caller-provided request IDs; anything else is replaced with a generated UUID.
+var requestIDRegExp = regexp.MustCompile(`^[A-Za-z0-9._:-]{1,64}$`)
+
+// Provides access to the repositories and configuration.
+// Used to give all route handlers access to .env variables and storage.
 type Handler struct {
-	Pool *pgxpool.Pool
-	Cfg  *config.Config
+	Cfg           *config.Config
+	Health        repository.HealthRepository
+	Clients       repository.ClientRepository
+	Leads         repository.LeadRepository
+	Usage         repository.UsageRepository
+	Notifications repository.NotificationRepository
+	Suppressions  repository.SuppressionRepository
+	Replies       repository.ReplyRepository
+	GeoIP         *services.GeoIP
+	Limiter       ratelimit.Limiter
+	HTTPClient    *http.Client
+	Lifecycle     *server.Lifecycle
 }
 
 // Sets up the Gin router with middleware (CORS, security headers, compression, body size, rate limiting),
 // applies API versioning and route definitions, and returns the configured Gin engine.
+// Background workers and resources owned by the handlers are registered with the lifecycle for shutdown.
 // Used to initialize the HTTP server.
-func Init(cfg *config.Config, db *pgxpool.Pool) *gin.Engine {
+// Clients, leads, usage, notifications, suppressions and replies are read and written through the store (Postgres in production, in-memory in tests).
+// The database pool is only used by the Postgres rate limiter and the pool metrics, so it may be nil without them.
+func Init(cfg *config.Config, db *pgxpool.Pool, store repository.Store, lifecycle *server.Lifecycle) (*gin.Engine, error) {
 	if cfg.GinMode == "release" {
 		gin.SetMode(gin.ReleaseMode)
 	} else {
 		gin.SetMode(gin.DebugMode)
 	}
 
-	router := gin.Default()
+	gin.DebugPrintFunc = func(format string, values ...any) {
+		slog.Debug(strings.TrimSpace(fmt.Sprintf(format, values...)))
+	}
+
+	router := gin.New()
 
+	if err := router.SetTrustedProxies(cfg.TrustedProxies); err != nil {
+		return nil, fmt.Errorf("unable to set the trusted proxies: %w", err)
+	}
+
+	router.Use(otelgin.Middleware(tracing.ServiceName))
+	router.Use(setRequestID())
+	router.Use(setLogger())
+	router.Use(setRecovery())
+	router.Use(metrics.Middleware())
 	router.Use(setCORS(cfg))
 	router.Use(setSecurityHeaders())
 	router.Use(setCompression())
 	router.Use(setBodySize())
-	router.Use(setRateLimiter(cfg))
 
-	handler := &Handler{Pool: db, Cfg: cfg}
+	geoIP, err := openGeoIP(cfg, lifecycle)
+	if err != nil {
+		return nil, err
+	}
+
+	handler := &Handler{
+		Cfg:           cfg,
+		Health:        store,
+		Clients:       store,
+		Leads:         store,
+		Usage:         store,
+		Notifications: store,
+		Suppressions:  store,
+		Replies:       store,
+		GeoIP:         geoIP,
+		Limiter:       newRateLimiter(cfg, db, lifecycle),
+		HTTPClient:    services.NewHTTPClient(time.Duration(cfg.ProviderTimeout) * time.Second),
+		Lifecycle:     lifecycle,
+	}
+
+	router.Use(handler.setRateLimiter())
+
+	handler.startEmailPoller()
+	registerPoolMetrics(db)
+
+	router.GET("/metrics", setMetricsAuth(cfg), metrics.Handler())
 
 	v1 := router.Group("/api/v1")
 
 	v1.GET("/health", handler.HealthHandler)
+	v1.GET("/health/live", handler.LivenessHandler)
+	v1.GET("/health/ready", handler.ReadinessHandler)
 
 	v1.POST("/leads/:id", handler.LeadHandler)
 
-	return router
+	v1.GET("/clients/:id/verify/email", handler.VerifyEmailPageHandler)
+	v1.POST("/clients/:id/verify/email", handler.VerifyEmailHandler)
+	v1.POST("/clients/:id/verify/phone", handler.VerifyPhoneHandler)
+
+	v1.POST("/webhooks/eventgrid", setWebhookAuth(cfg), handler.EventGridHandler)
+
+	admin := v1.Group("/admin", setAdminAuth(cfg))
+
+	admin.GET("/usage", handler.UsageReportHandler)
+	admin.GET("/clients/:id/usage", handler.UsageHandler)
+
+	admin.GET("/suppressions", handler.ListSuppressionsHandler)
+	admin.POST("/suppressions", handler.AddSuppressionHandler)
+	admin.DELETE("/suppressions/:channel/:recipient", handler.RemoveSuppressionHandler)
+
+	return router, nil
+}
+
+// Opens the optional GeoIP database used to locate lead submitters and closes it on shutdown.
+// Returns nil if no database is configured, which disables the lookup.
+func openGeoIP(cfg *config.Config, lifecycle *server.Lifecycle) (*services.GeoIP, error) {
+	if cfg.GeoIPDatabase == "" {
+		return nil, nil
+	}
+
+	geoIP, err := services.OpenGeoIP(cfg.GeoIPDatabase)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open the GeoIP database: %w", err)
+	}
+
+	lifecycle.OnStop("GeoIP database", func(ctx context.Context) error {
+		return geoIP.Close()
+	})
+
+	return geoIP, nil
+}
+
+// Polls the pending Azure email operations every EMAIL_POLL_INTERVAL seconds until they succeed or fail.
+// The poller is a background worker that stops on shutdown; it is disabled when the interval is zero.
+func (h *Handler) startEmailPoller() {
+	if h.Cfg.EmailPollInterval == 0 {
+		return
+	}
+
+	service := h.newService(nil)
+
+	h.Lifecycle.Go(func(ctx context.Context) {
+		ticker := time.NewTicker(time.Duration(h.Cfg.EmailPollInterval) * time.Second)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := service.PollEmailOperations(ctx); err != nil && ctx.Err() == nil {
+					slog.Error("Unable to poll the email operations", "error", err)
+				}
+			}
+		}
+	})
+}
+
+// Protects admin routes with the ADMIN_TOKEN bearer token.
+// Admin routes respond with 404 when no token is configured, so they are disabled by default.
+func setAdminAuth(cfg *config.Config) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if cfg.AdminToken == "" {
+			utils.Reject(c, http.StatusNotFound, "Not found.")
+			c.Abort()
+			return
+		}
+
+		if !hasBearerToken(c, cfg.AdminToken) {
+			utils.Reject(c, http.StatusUnauthorized, "Invalid admin token.")
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// Protects the metrics endpoint with the METRICS_TOKEN bearer token, if one is configured.
+// Without a token, metrics are public (e.g. when only reachable from an internal network).
+func setMetricsAuth(cfg *config.Config) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if cfg.MetricsToken != "" && !hasBearerToken(c, cfg.MetricsToken) {
+			utils.Reject(c, http.StatusUnauthorized, "Invalid metrics token.")
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// Compares the request's bearer token with the expected token in constant time.
+func hasBearerToken(c *gin.Context, token string) bool {
+	provided := strings.TrimPrefix(c.GetHeader("Authorization"), "Bearer ")
+
+	return subtle.ConstantTimeCompare([]byte(provided), []byte(token)) == 1
+}
+
+// Exposes the database pool statistics as metrics.
+// Registration is skipped without a pool, and repeated registrations (e.g. in tests) are ignored.
+func registerPoolMetrics(db *pgxpool.Pool) {
+	if db == nil {
+		return
+	}
+
+	var registered prometheus.AlreadyRegisteredError
+	if err := metrics.RegisterPool(db); err != nil && !errors.As(err, &registered) {
+		slog.Error("Unable to register the database pool metrics", "error", err)
+	}
+}
+
+// Generates an X-Request-ID for every request, or propagates the one sent by the caller (e.g. a load balancer).
+// The ID is returned in the response header, attached to every log line and included in the response meta.
+func setRequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetHeader("X-Request-ID")
+		if !requestIDRegExp.MatchString(id) {
+			id = uuid.NewString()
+		}
+
+		c.Request = c.Request.WithContext(logger.WithRequestID(c.Request.Context(), id))
+		c.Header("X-Request-ID", id)
+
+		c.Next()
+	}
+}
+
+// Logs every request as a structured line once it completes, replacing gin's text logger.
+func setLogger() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+
+		c.Next()
+
+		level := slog.LevelInfo
+		if c.Writer.Status() >= http.StatusInternalServerError {
+			level = slog.LevelError
+		}
+
+		slog.Log(
+			c.Request.Context(),
+			level,
+			"HTTP request",
+			"method", c.Request.Method,
+			"path", c.Request.URL.Path,
+			"route", c.FullPath(),
+			"status", c.Writer.Status(),
+			"latency_ms", time.Since(start).Milliseconds(),
+			"ip", c.ClientIP(),
+			"errors", c.Errors.ByType(gin.ErrorTypePrivate).String(),
+		)
+	}
+}
+
+// Recovers from panics in handlers, logs them with the stack trace and responds with 500.
+func setRecovery() gin.HandlerFunc {
+	return gin.CustomRecoveryWithWriter(io.Discard, func(c *gin.Context, err any) {
+		slog.ErrorContext(c.Request.Context(), "Recovered from panic", "error", err, "stack", string(debug.Stack()))
+
+		utils.Reject(c, http.StatusInternalServerError, "Internal server error.")
+		c.Abort()
+	})
 }
 
 // Configures CORS middleware using allowed origins from config.
@@ -57,8 +296,8 @@ func setCORS(cfg *config.Config) gin.HandlerFunc {
 	return cors.New(cors.Config{
 		AllowOrigins:     cfg.AllowedOrigins,
 		AllowMethods:     []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
-		AllowHeaders:     []string{"Origin", "Content-Type", "Accept", "Authorization"},
-		ExposeHeaders:    []string{"Content-Length"},
+		AllowHeaders:     []string{"Origin", "Content-Type", "Accept", "Authorization", "X-Request-ID"},
+		ExposeHeaders:    []string{"Content-Length", "X-Request-ID", "RateLimit-Limit", "RateLimit-Remaining", "RateLimit-Reset", "Retry-After"},
 		AllowCredentials: true,
 		MaxAge:           12 * time.Hour,
 	})
@@ -91,46 +330,3 @@ func setBodySize() gin.HandlerFunc {
 		c.Next()
 	}
 }
-
-var rateLimiterCache = cache.New(time.Minute, 5*time.Minute)
-
-// Returns a rate limiter for the given IP address, creating one if it doesn't exist.
-// Used to enforce per-IP rate limiting.
-func getRateLimiter(ip string, eventsPerSecond rate.Limit, burst int) *rate.Limiter {
-	limiter, found := rateLimiterCache.Get(ip)
-	if found {
-		return limiter.(*rate.Limiter)
-	}
-
-	limiter = rate.NewLimiter(eventsPerSecond, burst)
-	rateLimiterCache.Set(ip, limiter, cache.DefaultExpiration)
-
-	return limiter.(*rate.Limiter)
-}
-
-// Applies a rate limiter middleware to all requests based on client IP.
-// Returns HTTP 429 if the client exceeds the allowed request rate.
-func setRateLimiter(cfg *config.Config) gin.HandlerFunc {
-	eventsPerSecond := rate.Every(time.Duration(cfg.ThrottleTTL) * time.Second)
-	burst := cfg.ThrottleLimit
-
-	return func(c *gin.Context) {
-		ip := c.ClientIP()
-		limiter := getRateLimiter(ip, eventsPerSecond, burst)
-
-		if !limiter.Allow() {
-			c.AbortWithStatusJSON(http.StatusTooManyRequests, utils.APIResponse[utils.DefaultResponse]{
-				Meta: utils.Meta{
-					Status:    utils.StatusError,
-					Message:   "Too many requests. Please try again later.",
-					Timestamp: utils.GetCurrentTimestamp(),
-				},
-				Data: utils.DefaultResponse{},
-			})
-
-			return
-		}
-
-		c.Next()
-	}
-}
@@ -0,0 +1,120 @@
+package handlers
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"communications/internal/database/dto"
+	"communications/internal/database/models"
+	"communications/internal/repository"
+	"communications/internal/utils"
+)
+
+// Handles GET requests for the suppression list, newest first.
+// The list can be narrowed down to one channel with the "channel" query parameter (email, sms).
+func (h *Handler) ListSuppressionsHandler(c *gin.Context) {
+	channel, err := h.validateChannel(c, c.Query("channel"), true)
+	if err != nil {
+		return
+	}
+
+	suppressions, err := h.Suppressions.ListSuppressions(c.Request.Context(), channel)
+	if err != nil {
+		slog.ErrorContext(c.Request.Context(), "Unable to load the suppressions", "error", err)
+		utils.Reject(c, http.StatusInternalServerError, "Failed to load the suppressions.")
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.APIResponse[[]models.Suppression]{
+		Meta: utils.Meta{
+			Status:    utils.StatusSuccess,
+			Message:   "Suppressed recipients.",
+			Timestamp: utils.GetCurrentTimestamp(),
+			RequestID: utils.GetRequestID(c),
+		},
+		Data: suppressions,
+	})
+}
+
+// Handles POST requests suppressing an email address or phone number, so lead notifications skip it.
+// Suppressing a recipient again replaces its reason.
+func (h *Handler) AddSuppressionHandler(c *gin.Context) {
+	service := h.newService(c)
+
+	var body dto.SuppressionDTO
+	if err := c.ShouldBindJSON(&body); err != nil {
+		utils.Reject(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := body.Validate(); err != nil {
+		utils.Reject(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	suppression, err := service.Suppress(c.Request.Context(), body.Channel, body.Recipient, models.SuppressionAdmin, body.Reason)
+	if err != nil {
+		slog.ErrorContext(c.Request.Context(), "Unable to store the suppression", "channel", body.Channel, "error", err)
+		utils.Reject(c, http.StatusInternalServerError, "Failed to suppress the recipient.")
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.APIResponse[models.Suppression]{
+		Meta: utils.Meta{
+			Status:    utils.StatusSuccess,
+			Message:   "Recipient has been suppressed.",
+			Timestamp: utils.GetCurrentTimestamp(),
+			RequestID: utils.GetRequestID(c),
+		},
+		Data: suppression,
+	})
+}
+
+// Handles DELETE requests removing a recipient from the suppression list, so it receives notifications again.
+// The recipient is normalized like when it was suppressed, and unknown recipients return 404.
+func (h *Handler) RemoveSuppressionHandler(c *gin.Context) {
+	channel, err := h.validateChannel(c, c.Param("channel"), false)
+	if err != nil {
+		return
+	}
+
+	recipient := c.Param("recipient")
+	if !dto.NormalizeRecipient(channel, &recipient) {
+		utils.Reject(c, http.StatusBadRequest, "recipient must be a valid email address or a phone number in international format")
+		return
+	}
+
+	err = h.Suppressions.RemoveSuppression(c.Request.Context(), channel, recipient)
+	if errors.Is(err, repository.ErrNotFound) {
+		utils.Reject(c, http.StatusNotFound, "Recipient is not suppressed.")
+		return
+	}
+	if err != nil {
+		slog.ErrorContext(c.Request.Context(), "Unable to remove the suppression", "channel", channel, "error", err)
+		utils.Reject(c, http.StatusInternalServerError, "Failed to remove the suppression.")
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.APIResponse[utils.DefaultResponse]{
+		Meta: utils.Meta{
+			Status:    utils.StatusSuccess,
+			Message:   "Recipient is no longer suppressed.",
+			Timestamp: utils.GetCurrentTimestamp(),
+			RequestID: utils.GetRequestID(c),
+		},
+		Data: utils.DefaultResponse{},
+	})
+}
+
+// Ensures the channel is email or sms (or empty, if optional) before it reaches the suppression list.
+func (h *Handler) validateChannel(c *gin.Context, channel string, optional bool) (string, error) {
+	if channel == "email" || channel == "sms" || (optional && channel == "") {
+		return channel, nil
+	}
+
+	utils.Reject(c, http.StatusBadRequest, "channel must be email or sms")
+	return "", errors.New("invalid channel")
+}
@@ -0,0 +1,142 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"communications/internal/acstest"
+	"communications/internal/config"
+	"communications/internal/database/models"
+	"communications/internal/utils"
+)
+
+// Admin token in tests.
+const testAdminToken = "admin-token"
+
+// Checks if suppressed client contacts are skipped and recorded as suppressed notifications,
+// and the lead is still stored when both are suppressed.
+func TestLeadHandlerSuppressed(t *testing.T) {
+	tests := []struct {
+		name         string
+		suppressions []models.Suppression
+		message      string
+		emails       int // Email requests received by the provider.
+		sms          int // SMS requests received by the provider.
+		suppressed   int // Notifications stored as suppressed.
+	}{
+		{"Nothing suppressed", nil, "Email and SMS has been successfully sent", 1, 1, 0},
+		{"Email suppressed", []models.Suppression{{Channel: "email", Recipient: "client@example.com"}}, "SMS has been successfully sent", 0, 1, 1},
+		{"Phone suppressed", []models.Suppression{{Channel: "sms", Recipient: "+12025550199"}}, "Email has been successfully sent", 1, 0, 1},
+		{"Other channel's recipient", []models.Suppression{{Channel: "sms", Recipient: "client@example.com"}}, "Email and SMS has been successfully sent", 1, 1, 0},
+		{"Both suppressed", []models.Suppression{
+			{Channel: "email", Recipient: "client@example.com"},
+			{Channel: "sms", Recipient: "+12025550199"},
+		}, "Lead has been received", 0, 0, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router, store, azure := newTestRouter(t, func(cfg *config.Config, client *models.Client) {
+				client.Email = "Client@Example.com"
+			})
+
+			for _, suppression := range tt.suppressions {
+				suppression.Source = models.SuppressionAdmin
+				store.AddSuppression(context.Background(), &suppression)
+			}
+
+			response := postLead(router, testClientID, testLead)
+
+			if response.Code != http.StatusOK {
+				t.Fatalf("status = %d, want 200 (body %s)", response.Code, response.Body)
+			}
+
+			var body utils.APIResponse[utils.DefaultResponse]
+			if err := json.Unmarshal(response.Body.Bytes(), &body); err != nil || !strings.HasPrefix(body.Meta.Message, tt.message) {
+				t.Errorf("message = %q, %v, want it to start with %q", body.Meta.Message, err, tt.message)
+			}
+			if got := len(azure.Requests(acstest.Email)); got != tt.emails {
+				t.Errorf("email requests = %d, want %d", got, tt.emails)
+			}
+			if got := len(azure.Requests(acstest.SMS)); got != tt.sms {
+				t.Errorf("SMS requests = %d, want %d", got, tt.sms)
+			}
+			if got := len(store.Leads()); got != 1 {
+				t.Errorf("stored leads = %d, want 1", got)
+			}
+
+			suppressed := 0
+			for _, notification := range store.Notifications() {
+				if notification.Status == models.NotificationSuppressed {
+					suppressed++
+					if notification.MessageID != nil || notification.LeadID == nil {
+						t.Errorf("suppressed notification = %+v, want it linked to the lead without a message ID", notification)
+					}
+				}
+			}
+			if got := len(store.Notifications()); got != tt.emails+tt.sms+tt.suppressed || suppressed != tt.suppressed {
+				t.Errorf("stored notifications = %d (%d suppressed), want %d (%d suppressed)", got, suppressed, tt.emails+tt.sms+tt.suppressed, tt.suppressed)
+			}
+		})
+	}
+}
+
+// Checks if the admin API adds, lists and removes suppressions, normalizing the recipients.
+func TestSuppressionHandlers(t *testing.T) {
+	router, _, _ := newTestRouter(t, func(cfg *config.Config, client *models.Client) {
+		cfg.AdminToken = testAdminToken
+	})
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		body   string
+		status int
+		count  int // Suppressions listed afterwards.
+	}{
+		{"Add email", http.MethodPost, "/suppressions", `{"channel": "email", "recipient": " Client@Example.com ", "reason": "Asked to stop"}`, http.StatusOK, 1},
+		{"Add phone", http.MethodPost, "/suppressions", `{"channel": "sms", "recipient": "+1 (202) 555-0199"}`, http.StatusOK, 2},
+		{"Add again", http.MethodPost, "/suppressions", `{"channel": "email", "recipient": "client@example.com"}`, http.StatusOK, 2},
+		{"Invalid channel", http.MethodPost, "/suppressions", `{"channel": "fax", "recipient": "client@example.com"}`, http.StatusBadRequest, 2},
+		{"Invalid recipient", http.MethodPost, "/suppressions", `{"channel": "sms", "recipient": "client@example.com"}`, http.StatusBadRequest, 2},
+		{"List by invalid channel", http.MethodGet, "/suppressions?channel=fax", "", http.StatusBadRequest, 2},
+		{"Remove email", http.MethodDelete, "/suppressions/email/CLIENT@example.com", "", http.StatusOK, 1},
+		{"Remove missing", http.MethodDelete, "/suppressions/email/client@example.com", "", http.StatusNotFound, 1},
+		{"Remove invalid phone", http.MethodDelete, "/suppressions/sms/12", "", http.StatusBadRequest, 1},
+		{"Remove phone", http.MethodDelete, "/suppressions/sms/+12025550199", "", http.StatusOK, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			response := adminRequest(router, tt.method, tt.path, tt.body)
+			if response.Code != tt.status {
+				t.Fatalf("status = %d, want %d (body %s)", response.Code, tt.status, response.Body)
+			}
+
+			var list utils.APIResponse[[]models.Suppression]
+			response = adminRequest(router, http.MethodGet, "/suppressions", "")
+			if err := json.Unmarshal(response.Body.Bytes(), &list); err != nil || len(list.Data) != tt.count {
+				t.Fatalf("listed suppressions = %s, %v, want %d", response.Body, err, tt.count)
+			}
+		})
+	}
+}
+
+// Sends an authenticated admin request.
+func adminRequest(router *gin.Engine, method, path, body string) *httptest.ResponseRecorder {
+	request := httptest.NewRequest(method, "/api/v1/admin"+path, strings.NewReader(body))
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("Authorization", "Bearer "+testAdminToken)
+
+	response := httptest.NewRecorder()
+	router.ServeHTTP(response, request)
+
+	return response
+}
@@ -0,0 +1,115 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"communications/internal/database/models"
+	"communications/internal/services"
+	"communications/internal/utils"
+)
+
+// Handles GET requests for a client's notification usage in the current billing period.
+// Returns the email and SMS counters together with the client's effective monthly quotas.
+func (h *Handler) UsageHandler(c *gin.Context) {
+	service := h.newService(c)
+
+	id, err := h.validateID(c)
+	if err != nil {
+		return
+	}
+
+	client, err := h.findClientByID(c, id)
+	if err != nil {
+		return
+	}
+
+	usage, err := service.GetUsage(c.Request.Context(), client)
+	if err != nil {
+		utils.Reject(c, http.StatusInternalServerError, "Failed to load the usage.")
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.APIResponse[models.Usage]{
+		Meta: utils.Meta{
+			Status:    utils.StatusSuccess,
+			Message:   "Usage for the current billing period.",
+			Timestamp: utils.GetCurrentTimestamp(),
+			RequestID: utils.GetRequestID(c),
+		},
+		Data: usage,
+	})
+}
+
+// Handles GET requests for the monthly usage report of all clients.
+// The billing period is selected with the "month" query parameter (YYYY-MM), defaulting to the current month.
+func (h *Handler) UsageReportHandler(c *gin.Context) {
+	service := h.newService(c)
+
+	month := time.Now()
+	if value := c.Query("month"); value != "" {
+		parsed, err := time.Parse("2006-01", value)
+		if err != nil {
+			utils.Reject(c, http.StatusBadRequest, "month must be in YYYY-MM format")
+			return
+		}
+		month = parsed
+	}
+
+	report, err := service.UsageReport(c.Request.Context(), month)
+	if err != nil {
+		utils.Reject(c, http.StatusInternalServerError, "Failed to load the usage report.")
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.APIResponse[[]models.Usage]{
+		Meta: utils.Meta{
+			Status:    utils.StatusSuccess,
+			Message:   "Usage report for " + month.Format("2006-01") + ".",
+			Timestamp: utils.GetCurrentTimestamp(),
+			RequestID: utils.GetRequestID(c),
+		},
+		Data: report,
+	})
+}
+
+// Reserves the notification channels the client's monthly quotas still allow, before anything is sent.
+// Channels already skipped (e.g. unverified contacts) keep their reason, and exhausted ones are skipped with
+// services.ErrQuotaExceeded. Rejects the lead with 429 if the quota mode does not allow sending it at all,
+// or if no channel is left to notify. The reservation outlives the request, like the sends it pays for.
+func (h *Handler) reserveQuota(c *gin.Context, service *services.Service, client *models.Client, skipEmail, skipSMS error) (error, error, error) {
+	ctx := context.WithoutCancel(c.Request.Context())
+
+	sendEmail, sendSMS, err := service.ReserveQuota(ctx, client, skipEmail == nil, skipSMS == nil)
+	if errors.Is(err, services.ErrQuotaExceeded) {
+		utils.Reject(c, http.StatusTooManyRequests, "Monthly notification quota exceeded for this client.")
+		return nil, nil, err
+	}
+	if err != nil {
+		slog.ErrorContext(ctx, "Unable to reserve the notification quota", "client_id", client.ID, "error", err)
+		utils.Reject(c, http.StatusInternalServerError, "Failed to check the notification quota.")
+		return nil, nil, err
+	}
+
+	if !sendEmail && skipEmail == nil {
+		skipEmail = services.ErrQuotaExceeded
+	}
+	if !sendSMS && skipSMS == nil {
+		skipSMS = services.ErrQuotaExceeded
+	}
+
+	return skipEmail, skipSMS, nil
+}
+
+// Releases the quota reserved for channels that were attempted but not sent (failed or suppressed recipients),
+// so only sent notifications are metered. Channels skipped before sending never reserved any quota.
+func (h *Handler) releaseQuota(c *gin.Context, service *services.Service, client *models.Client, skipEmail, skipSMS error, email, sms delivery) {
+	ctx := context.WithoutCancel(c.Request.Context())
+
+	service.ReleaseQuota(ctx, client.ID, skipEmail == nil && email.err != nil, skipSMS == nil && sms.err != nil)
+}
@@ -0,0 +1,141 @@
+package handlers
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"communications/internal/database/dto"
+	"communications/internal/database/models"
+	"communications/internal/repository"
+	"communications/internal/services"
+	"communications/internal/utils"
+)
+
+// Handles GET requests from the verification link emailed to a client.
+// Only checks the link and serves a page confirming with a POST, so link scanners fetching it verify nothing.
+func (h *Handler) VerifyEmailPageHandler(c *gin.Context) {
+	service := h.newService(c)
+
+	id, err := h.validateID(c)
+	if err != nil {
+		return
+	}
+
+	client, err := h.findClientByID(c, id)
+	if err != nil {
+		return
+	}
+
+	expires, signature := c.Query("expires"), c.Query("signature")
+
+	err = service.CheckLink(client, expires, signature)
+	if h.rejectVerification(c, client, err, services.ErrInvalidLink) {
+		return
+	}
+
+	c.Header("Cache-Control", "no-store")
+	c.Data(http.StatusOK, "text/html; charset=utf-8", []byte(services.ConfirmationHTML(client.Name, expires, signature)))
+}
+
+// Handles POST requests from the confirmation page of the verification link.
+// Checks the link's signature and expiry, and marks the client's email as verified.
+func (h *Handler) VerifyEmailHandler(c *gin.Context) {
+	service := h.newService(c)
+
+	id, err := h.validateID(c)
+	if err != nil {
+		return
+	}
+
+	client, err := h.findClientByID(c, id)
+	if err != nil {
+		return
+	}
+
+	err = service.VerifyEmail(c.Request.Context(), client, c.PostForm("expires"), c.PostForm("signature"))
+	if h.rejectVerification(c, client, err, services.ErrInvalidLink) {
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.APIResponse[utils.DefaultResponse]{
+		Meta: utils.Meta{
+			Status:    utils.StatusSuccess,
+			Message:   "Email address has been verified.",
+			Timestamp: utils.GetCurrentTimestamp(),
+			RequestID: utils.GetRequestID(c),
+		},
+		Data: utils.DefaultResponse{},
+	})
+}
+
+// Handles POST requests with the code sent to a client by SMS.
+// Marks the client's phone as verified if the code matches; each code allows a limited number of attempts.
+func (h *Handler) VerifyPhoneHandler(c *gin.Context) {
+	service := h.newService(c)
+
+	id, err := h.validateID(c)
+	if err != nil {
+		return
+	}
+
+	client, err := h.findClientByID(c, id)
+	if err != nil {
+		return
+	}
+
+	var body dto.VerifyPhoneDTO
+	if err := c.ShouldBindJSON(&body); err != nil {
+		utils.Reject(c, http.StatusBadRequest, "code must be a 6-digit number")
+		return
+	}
+
+	err = service.VerifyPhone(c.Request.Context(), client, body.Code)
+	if h.rejectVerification(c, client, err, services.ErrInvalidCode) {
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.APIResponse[utils.DefaultResponse]{
+		Meta: utils.Meta{
+			Status:    utils.StatusSuccess,
+			Message:   "Phone number has been verified.",
+			Timestamp: utils.GetCurrentTimestamp(),
+			RequestID: utils.GetRequestID(c),
+		},
+		Data: utils.DefaultResponse{},
+	})
+}
+
+// Decides which of the client's contacts may receive the lead under UNVERIFIED_MODE.
+// Returns services.ErrUnverified for each skipped contact, and rejects the lead with 403 if none is left.
+func (h *Handler) checkVerification(c *gin.Context, service *services.Service, client *models.Client) (error, error, error) {
+	skipEmail, skipSMS := service.CheckVerification(client)
+
+	if skipEmail != nil && skipSMS != nil {
+		utils.Reject(c, http.StatusForbidden, "Client contacts are not verified.")
+		return nil, nil, services.ErrUnverified
+	}
+
+	return skipEmail, skipSMS, nil
+}
+
+// Responds to a failed verification: 400 for invalid input, 429 for too many attempts and 500 otherwise.
+// A contact that changed since the link or code was sent is reported as invalid.
+// Returns true if the request was rejected.
+func (h *Handler) rejectVerification(c *gin.Context, client *models.Client, err, invalid error) bool {
+	switch {
+	case err == nil:
+		return false
+	case errors.Is(err, services.ErrTooManyAttempts):
+		utils.Reject(c, http.StatusTooManyRequests, err.Error())
+	case errors.Is(err, invalid), errors.Is(err, repository.ErrNotFound):
+		utils.Reject(c, http.StatusBadRequest, invalid.Error())
+	default:
+		slog.ErrorContext(c.Request.Context(), "Unable to verify the client", "client_id", client.ID, "error", err)
+		utils.Reject(c, http.StatusInternalServerError, "Failed to verify the client.")
+	}
+
+	return true
+}
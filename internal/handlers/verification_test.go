@@ -0,0 +1,253 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"html"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"communications/internal/acstest"
+	"communications/internal/config"
+	"communications/internal/database/models"
+	"communications/internal/repository"
+	"communications/internal/services"
+)
+
+// Matches the verification link in the verification email.
+var linkRegExp = regexp.MustCompile(`href="([^"]+)"`)
+
+// Matches the code in the verification SMS.
+var codeRegExp = regexp.MustCompile(`\b\d{6}\b`)
+
+// Checks if leads only notify verified contacts, unless unverified ones are configured to only warn.
+func TestLeadHandlerUnverified(t *testing.T) {
+	tests := []struct {
+		name          string
+		mode          string
+		emailVerified bool
+		phoneVerified bool
+		status        int
+		emails        int // Email requests received by the provider.
+		sms           int // SMS requests received by the provider.
+		leads         int // Leads stored.
+	}{
+		{"Nothing verified", services.UnverifiedModeBlock, false, false, http.StatusForbidden, 0, 0, 0},
+		{"Only email verified", services.UnverifiedModeBlock, true, false, http.StatusOK, 1, 0, 1},
+		{"Only phone verified", services.UnverifiedModeBlock, false, true, http.StatusOK, 0, 1, 1},
+		{"Warn mode", services.UnverifiedModeWarn, false, false, http.StatusOK, 1, 1, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router, store, azure := newTestRouter(t, func(cfg *config.Config, client *models.Client) {
+				cfg.UnverifiedMode = tt.mode
+				client.EmailVerified, client.PhoneVerified = tt.emailVerified, tt.phoneVerified
+				client.Verified = tt.emailVerified && tt.phoneVerified
+			})
+
+			response := postLead(router, testClientID, testLead)
+
+			if response.Code != tt.status {
+				t.Fatalf("status = %d, want %d (body %s)", response.Code, tt.status, response.Body)
+			}
+			if got := len(azure.Requests(acstest.Email)); got != tt.emails {
+				t.Errorf("email requests = %d, want %d", got, tt.emails)
+			}
+			if got := len(azure.Requests(acstest.SMS)); got != tt.sms {
+				t.Errorf("SMS requests = %d, want %d", got, tt.sms)
+			}
+			if got := len(store.Leads()); got != tt.leads {
+				t.Errorf("stored leads = %d, want %d", got, tt.leads)
+			}
+		})
+	}
+}
+
+// Checks if the emailed link only serves a confirmation page, the confirmation verifies the email,
+// and tampered or expired links are rejected.
+func TestVerifyEmailHandler(t *testing.T) {
+	router, store, azure, service := newVerificationTest(t)
+	client, _ := store.FindClient(context.Background(), testClientID)
+
+	emailError, smsError := service.SendVerification(context.Background(), &client)
+	if emailError != nil || smsError != nil {
+		t.Fatalf("SendVerification() = %v, %v", emailError, smsError)
+	}
+
+	match := linkRegExp.FindStringSubmatch(azure.Requests(acstest.Email)[0].Email.Content.HTML)
+	if match == nil {
+		t.Fatal("verification email does not contain a link")
+	}
+
+	link, err := url.Parse(html.UnescapeString(match[1]))
+	if err != nil || link.Host != "localhost:5000" {
+		t.Fatalf("verification link = %q, %v", match[1], err)
+	}
+
+	tampered := *link
+	query := tampered.Query()
+	query.Set("expires", "9999999999")
+	tampered.RawQuery = query.Encode()
+
+	expired, _ := url.Parse(service.VerificationLink(&client, time.Now().Add(-time.Minute)))
+
+	tests := []struct {
+		name     string
+		method   string
+		link     *url.URL
+		status   int
+		verified bool
+	}{
+		{"Tampered link", http.MethodGet, &tampered, http.StatusBadRequest, false},
+		{"Expired link", http.MethodGet, expired, http.StatusBadRequest, false},
+		{"Link opened", http.MethodGet, link, http.StatusOK, false},
+		{"Tampered confirmation", http.MethodPost, &tampered, http.StatusBadRequest, false},
+		{"Expired confirmation", http.MethodPost, expired, http.StatusBadRequest, false},
+		{"Link confirmed", http.MethodPost, link, http.StatusOK, true},
+		{"Link reused", http.MethodPost, link, http.StatusOK, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			response := httptest.NewRecorder()
+			router.ServeHTTP(response, newLinkRequest(tt.method, tt.link))
+
+			if response.Code != tt.status {
+				t.Fatalf("status = %d, want %d (body %s)", response.Code, tt.status, response.Body)
+			}
+			if tt.method == http.MethodGet && tt.status == http.StatusOK && !strings.Contains(response.Body.String(), `<form method="post"`) {
+				t.Errorf("confirmation page %s does not contain a POST form", response.Body)
+			}
+
+			client, _ := store.FindClient(context.Background(), testClientID)
+			if client.EmailVerified != tt.verified || client.Verified {
+				t.Errorf("email verified = %v, client verified = %v, want %v and false", client.EmailVerified, client.Verified, tt.verified)
+			}
+		})
+	}
+}
+
+// Checks if the SMS code verifies the phone, and wrong codes count towards the attempt limit.
+func TestVerifyPhoneHandler(t *testing.T) {
+	tests := []struct {
+		name     string
+		guesses  int    // Wrong codes entered before the sent one.
+		code     string // Submitted code: "sent", "wrong" or a literal value.
+		status   int
+		verified bool
+	}{
+		{"Valid code", 0, "sent", http.StatusOK, true},
+		{"Valid code after wrong guesses", 4, "sent", http.StatusOK, true},
+		{"Too many attempts", 5, "sent", http.StatusTooManyRequests, false},
+		{"Wrong code", 0, "wrong", http.StatusBadRequest, false},
+		{"Malformed code", 0, "12ab56", http.StatusBadRequest, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router, store, azure, service := newVerificationTest(t)
+			client, _ := store.FindClient(context.Background(), testClientID)
+
+			if _, err := service.SendVerification(context.Background(), &client); err != nil {
+				t.Fatalf("SendVerification() error = %v", err)
+			}
+
+			sent := codeRegExp.FindString(azure.Requests(acstest.SMS)[0].SMS.Message)
+			if sent == "" {
+				t.Fatal("verification SMS does not contain a code")
+			}
+
+			wrong := "000000"
+			if sent == wrong {
+				wrong = "111111"
+			}
+
+			for range tt.guesses {
+				postCode(router, wrong)
+			}
+
+			code := map[string]string{"sent": sent, "wrong": wrong}[tt.code]
+			if code == "" {
+				code = tt.code
+			}
+
+			response := postCode(router, code)
+			if response.Code != tt.status {
+				t.Fatalf("status = %d, want %d (body %s)", response.Code, tt.status, response.Body)
+			}
+
+			client, _ = store.FindClient(context.Background(), testClientID)
+			if client.PhoneVerified != tt.verified || client.Verified {
+				t.Errorf("phone verified = %v, client verified = %v, want %v and false", client.PhoneVerified, client.Verified, tt.verified)
+			}
+		})
+	}
+}
+
+// Checks if verification messages are not sent to suppressed contacts, e.g. a phone that replied STOP.
+func TestSendVerificationSuppressed(t *testing.T) {
+	_, store, azure, service := newVerificationTest(t)
+	client, _ := store.FindClient(context.Background(), testClientID)
+
+	store.AddSuppression(context.Background(), &models.Suppression{Channel: "sms", Recipient: client.Phone, Source: models.SuppressionOptOut})
+
+	emailError, smsError := service.SendVerification(context.Background(), &client)
+	if emailError != nil || !errors.Is(smsError, services.ErrSuppressed) {
+		t.Fatalf("SendVerification() = %v, %v, want nil and ErrSuppressed", emailError, smsError)
+	}
+	if got := len(azure.Requests(acstest.SMS)); got != 0 {
+		t.Errorf("SMS requests = %d, want 0", got)
+	}
+	if got := len(azure.Requests(acstest.Email)); got != 1 {
+		t.Errorf("email requests = %d, want 1", got)
+	}
+}
+
+// Boots the router with an unverified client and returns a service sending verifications through the same fakes.
+func newVerificationTest(t *testing.T) (*gin.Engine, *repository.Memory, *acstest.Server, *services.Service) {
+	t.Helper()
+
+	var testConfig *config.Config
+
+	router, store, azure := newTestRouter(t, func(cfg *config.Config, client *models.Client) {
+		client.Verified, client.EmailVerified, client.PhoneVerified = false, false, false
+		testConfig = cfg
+	})
+
+	service := services.NewService(store, testConfig)
+	service.HTTPClient = services.NewHTTPClient(time.Second)
+
+	return router, store, azure, service
+}
+
+// Submits a phone verification code for the test client.
+func postCode(router *gin.Engine, code string) *httptest.ResponseRecorder {
+	body := `{"code": "` + code + `"}`
+	request := httptest.NewRequest(http.MethodPost, "/api/v1/clients/"+testClientID+"/verify/phone", strings.NewReader(body))
+	request.Header.Set("Content-Type", "application/json")
+
+	response := httptest.NewRecorder()
+	router.ServeHTTP(response, request)
+
+	return response
+}
+
+// Opens a verification link, or submits its confirmation form with the link's parameters.
+func newLinkRequest(method string, link *url.URL) *http.Request {
+	if method == http.MethodGet {
+		return httptest.NewRequest(method, link.RequestURI(), nil)
+	}
+
+	request := httptest.NewRequest(method, link.Path, strings.NewReader(link.Query().Encode()))
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	return request
+}
@@ -0,0 +1,90 @@
+package handlers
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"communications/internal/config"
+	"communications/internal/database/dto"
+	"communications/internal/utils"
+)
+
+// Handles POST requests from the Azure Event Grid subscription for Communication Services events.
+// Answers the subscription validation handshake, updates the notifications of delivery report events
+// and handles inbound SMS. Responds with 500 if an event cannot be stored, so Event Grid redelivers the batch.
+func (h *Handler) EventGridHandler(c *gin.Context) {
+	service := h.newService(c)
+
+	var events []dto.EventGridEvent
+	if err := c.ShouldBindJSON(&events); err != nil {
+		utils.Reject(c, http.StatusBadRequest, "body must be an array of Event Grid events")
+		return
+	}
+
+	for _, event := range events {
+		if event.EventType != dto.SubscriptionValidationEvent {
+			continue
+		}
+
+		var data dto.SubscriptionValidationData
+		if err := json.Unmarshal(event.Data, &data); err != nil || data.ValidationCode == "" {
+			utils.Reject(c, http.StatusBadRequest, "validation event has no validation code")
+			return
+		}
+
+		slog.InfoContext(c.Request.Context(), "Validated the Event Grid subscription", "event_id", event.ID)
+		c.JSON(http.StatusOK, dto.SubscriptionValidationResponse{ValidationResponse: data.ValidationCode})
+		return
+	}
+
+	for _, event := range events {
+		var err error
+
+		if event.EventType == dto.SMSReceivedEvent {
+			err = service.HandleInboundSMS(c.Request.Context(), event)
+		} else {
+			err = service.ApplyDeliveryReport(c.Request.Context(), event)
+		}
+
+		if err != nil {
+			slog.ErrorContext(c.Request.Context(), "Unable to process the event", "event_id", event.ID, "event_type", event.EventType, "error", err)
+			utils.Reject(c, http.StatusInternalServerError, "Failed to process the events.")
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, utils.APIResponse[utils.DefaultResponse]{
+		Meta: utils.Meta{
+			Status:    utils.StatusSuccess,
+			Message:   "Events processed.",
+			Timestamp: utils.GetCurrentTimestamp(),
+			RequestID: utils.GetRequestID(c),
+		},
+		Data: utils.DefaultResponse{},
+	})
+}
+
+// Protects the Event Grid webhook with the WEBHOOK_TOKEN passed in the "token" query parameter,
+// since Event Grid cannot send custom authorization headers to plain webhooks.
+// The webhook responds with 404 when no token is configured, so it is disabled by default.
+func setWebhookAuth(cfg *config.Config) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if cfg.WebhookToken == "" {
+			utils.Reject(c, http.StatusNotFound, "Not found.")
+			c.Abort()
+			return
+		}
+
+		if subtle.ConstantTimeCompare([]byte(c.Query("token")), []byte(cfg.WebhookToken)) != 1 {
+			utils.Reject(c, http.StatusUnauthorized, "Invalid webhook token.")
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
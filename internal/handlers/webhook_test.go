@@ -0,0 +1,298 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"communications/internal/acstest"
+	"communications/internal/config"
+	"communications/internal/database/dto"
+	"communications/internal/database/models"
+	"communications/internal/repository"
+	"communications/internal/services"
+)
+
+// Token of the Event Grid webhook in tests.
+const testWebhookToken = "webhook-token"
+
+// Checks if the webhook is disabled without a token and rejects requests with a wrong token.
+func TestEventGridAuth(t *testing.T) {
+	tests := []struct {
+		name   string
+		token  string // Configured WEBHOOK_TOKEN.
+		query  string
+		status int
+	}{
+		{"Disabled", "", "?token=anything", http.StatusNotFound},
+		{"Missing token", testWebhookToken, "", http.StatusUnauthorized},
+		{"Wrong token", testWebhookToken, "?token=wrong", http.StatusUnauthorized},
+		{"Valid token", testWebhookToken, "?token=" + testWebhookToken, http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router, _, _ := newTestRouter(t, func(cfg *config.Config, client *models.Client) {
+				cfg.WebhookToken = tt.token
+			})
+
+			response := postEvents(router, tt.query, `[]`)
+			if response.Code != tt.status {
+				t.Errorf("status = %d, want %d (body %s)", response.Code, tt.status, response.Body)
+			}
+		})
+	}
+}
+
+// Checks if the subscription validation handshake echoes the validation code.
+func TestEventGridValidation(t *testing.T) {
+	router, _, _ := newWebhookRouter(t)
+
+	response := postEvents(router, "?token="+testWebhookToken, `[{
+		"id": "2d1781af-3a4c-4d7c-bd0c-e34b19da4e66",
+		"eventType": "Microsoft.EventGrid.SubscriptionValidationEvent",
+		"subject": "",
+		"eventTime": "2025-06-15T12:00:00Z",
+		"data": {"validationCode": "512d38b6-c7b8-40c8-89fe-f46f9e9622b6", "validationUrl": "https://example.com"}
+	}]`)
+
+	if response.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200 (body %s)", response.Code, response.Body)
+	}
+
+	var body dto.SubscriptionValidationResponse
+	if err := json.Unmarshal(response.Body.Bytes(), &body); err != nil || body.ValidationResponse != "512d38b6-c7b8-40c8-89fe-f46f9e9622b6" {
+		t.Errorf("validation response = %s, %v", response.Body, err)
+	}
+}
+
+// Checks if the notifications sent for a lead are stored and updated by delivery reports,
+// leaving the other channel's notification untouched.
+func TestEventGridDeliveryReports(t *testing.T) {
+	tests := []struct {
+		name       string
+		eventType  string
+		channel    string
+		data       string // Report data; %s is replaced with the message ID of the channel's notification.
+		status     string
+		reason     string
+		suppressed bool // Whether the recipient is suppressed afterwards.
+	}{
+		{"Email delivered", dto.EmailDeliveryReportEvent, "email",
+			`{"messageId": "%s", "status": "Delivered", "deliveryStatusDetails": {"statusMessage": ""}}`,
+			models.NotificationDelivered, "Delivered", false},
+		{"Email bounced", dto.EmailDeliveryReportEvent, "email",
+			`{"recipient": "Client@Example.com", "messageId": "%s", "status": "Bounced", "deliveryStatusDetails": {"statusMessage": "Mailbox does not exist"}}`,
+			models.NotificationBounced, "Bounced: Mailbox does not exist", true},
+		{"Email suppressed", dto.EmailDeliveryReportEvent, "email",
+			`{"messageId": "%s", "status": "Suppressed", "deliveryStatusDetails": {"statusMessage": "Recipient is suppressed"}}`,
+			models.NotificationFailed, "Suppressed: Recipient is suppressed", false},
+		{"Email expanded", dto.EmailDeliveryReportEvent, "email",
+			`{"messageId": "%s", "status": "Expanded"}`,
+			models.NotificationAccepted, "", false},
+		{"SMS delivered", dto.SMSDeliveryReportEvent, "sms",
+			`{"messageId": "%s", "deliveryStatus": "Delivered", "deliveryStatusDetails": "No error."}`,
+			models.NotificationDelivered, "Delivered: No error.", false},
+		{"SMS failed", dto.SMSDeliveryReportEvent, "sms",
+			`{"messageId": "%s", "deliveryStatus": "Failed", "deliveryStatusDetails": "Unreachable handset."}`,
+			models.NotificationFailed, "Failed: Unreachable handset.", false},
+		{"Unknown message", dto.SMSDeliveryReportEvent, "sms",
+			`{"messageId": "unknown-%s", "deliveryStatus": "Delivered"}`,
+			models.NotificationAccepted, "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router, store, _ := newWebhookRouter(t)
+
+			if response := postLead(router, testClientID, testLead); response.Code != http.StatusOK {
+				t.Fatalf("lead status = %d, want 200 (body %s)", response.Code, response.Body)
+			}
+
+			notifications := map[string]models.Notification{}
+			for _, notification := range store.Notifications() {
+				notifications[notification.Channel] = notification
+			}
+
+			sent, ok := notifications[tt.channel]
+			if !ok || sent.MessageID == nil || sent.LeadID == nil || *sent.LeadID != store.Leads()[0].ID || sent.Status != models.NotificationAccepted {
+				t.Fatalf("stored notifications = %+v", notifications)
+			}
+
+			data := strings.Replace(tt.data, "%s", *sent.MessageID, 1)
+			response := postEvents(router, "?token="+testWebhookToken, `[{
+				"id": "event-1",
+				"eventType": "`+tt.eventType+`",
+				"subject": "deliveryreport",
+				"eventTime": "2025-06-15T12:00:00Z",
+				"data": `+data+`
+			}]`)
+			if response.Code != http.StatusOK {
+				t.Fatalf("status = %d, want 200 (body %s)", response.Code, response.Body)
+			}
+
+			for _, notification := range store.Notifications() {
+				want := models.NotificationAccepted
+				if notification.Channel == tt.channel {
+					want = tt.status
+				}
+				if notification.Status != want {
+					t.Errorf("%s status = %q, want %q", notification.Channel, notification.Status, want)
+				}
+				if notification.Channel == tt.channel && tt.reason != "" && (notification.Reason == nil || *notification.Reason != tt.reason) {
+					t.Errorf("%s reason = %v, want %q", notification.Channel, notification.Reason, tt.reason)
+				}
+			}
+
+			suppression, err := store.FindSuppression(context.Background(), "email", "client@example.com")
+			if (err == nil) != tt.suppressed || (tt.suppressed && suppression.Source != models.SuppressionBounce) {
+				t.Errorf("suppression = %+v, %v, want suppressed %v", suppression, err, tt.suppressed)
+			}
+		})
+	}
+}
+
+// Checks if a recent report for a message that is not stored as a notification (e.g. a verification SMS)
+// is acknowledged rather than retried, so it does not fail the other events of the batch.
+func TestEventGridUnknownReport(t *testing.T) {
+	router, store, _ := newWebhookRouter(t)
+
+	response := postEvents(router, "?token="+testWebhookToken, `[{
+		"id": "event-1",
+		"eventType": "`+dto.SMSDeliveryReportEvent+`",
+		"subject": "deliveryreport",
+		"eventTime": "`+time.Now().UTC().Format(time.RFC3339)+`",
+		"data": {"messageId": "verification-message", "deliveryStatus": "Delivered"}
+	}]`)
+	if response.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200 (body %s)", response.Code, response.Body)
+	}
+	if notifications := store.Notifications(); len(notifications) != 0 {
+		t.Errorf("notifications = %+v, want none", notifications)
+	}
+}
+
+// Checks if the webhook is not throttled by the per-IP rate limit, since Event Grid sends one event per request.
+func TestEventGridRateLimit(t *testing.T) {
+	router, _, _ := newTestRouter(t, func(cfg *config.Config, client *models.Client) {
+		cfg.WebhookToken = testWebhookToken
+		cfg.ThrottleLimit = 1
+	})
+
+	for i := range 5 {
+		if response := postEvents(router, "?token="+testWebhookToken, `[]`); response.Code != http.StatusOK {
+			t.Fatalf("request %d status = %d, want 200 (body %s)", i+1, response.Code, response.Body)
+		}
+	}
+}
+
+// Checks if polling records the final status of email operations, failing the notification
+// only if no delivery report arrived first.
+func TestPollEmailOperations(t *testing.T) {
+	tests := []struct {
+		name      string
+		delivered bool   // Whether a delivery report arrives before the poll.
+		operation string // Operation status set on the provider; empty leaves it running.
+		completed string // Expected operation status; empty when still pending.
+		status    string
+		reason    string
+	}{
+		{"Running", false, "", "", models.NotificationAccepted, ""},
+		{"Succeeded", false, "Succeeded", "Succeeded", models.NotificationAccepted, ""},
+		{"Failed", false, "Failed", "Failed", models.NotificationFailed, "Failed: Message was not sent."},
+		{"Canceled", false, "Canceled", "Canceled", models.NotificationFailed, "Canceled: Message was not sent."},
+		{"Failed after delivery", true, "Failed", "Failed", models.NotificationDelivered, "Delivered"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var testConfig *config.Config
+
+			router, store, azure := newTestRouter(t, func(cfg *config.Config, client *models.Client) {
+				cfg.WebhookToken = testWebhookToken
+				testConfig = cfg
+			})
+
+			service := services.NewService(store, testConfig)
+			service.HTTPClient = services.NewHTTPClient(time.Second)
+
+			if response := postLead(router, testClientID, testLead); response.Code != http.StatusOK {
+				t.Fatalf("lead status = %d, want 200 (body %s)", response.Code, response.Body)
+			}
+
+			email := findNotification(t, store, "email")
+
+			if tt.delivered {
+				postEvents(router, "?token="+testWebhookToken, `[{
+					"id": "event-1",
+					"eventType": "`+dto.EmailDeliveryReportEvent+`",
+					"subject": "deliveryreport",
+					"eventTime": "2025-06-15T12:00:00Z",
+					"data": {"messageId": "`+*email.MessageID+`", "status": "Delivered"}
+				}]`)
+			}
+			if tt.operation != "" {
+				azure.CompleteOperation(*email.MessageID, tt.operation)
+			}
+
+			if err := service.PollEmailOperations(context.Background()); err != nil {
+				t.Fatalf("PollEmailOperations() error = %v", err)
+			}
+
+			email = findNotification(t, store, "email")
+
+			if got := email.OperationStatus; (got == nil && tt.completed != "") || (got != nil && *got != tt.completed) {
+				t.Errorf("operation status = %v, want %q", got, tt.completed)
+			}
+			if email.Status != tt.status {
+				t.Errorf("status = %q, want %q", email.Status, tt.status)
+			}
+			if tt.reason != "" && (email.Reason == nil || *email.Reason != tt.reason) {
+				t.Errorf("reason = %v, want %q", email.Reason, tt.reason)
+			}
+			if sms := findNotification(t, store, "sms"); sms.OperationStatus != nil || sms.Status != models.NotificationAccepted {
+				t.Errorf("SMS notification = %+v, want it untouched", sms)
+			}
+		})
+	}
+}
+
+// Returns the stored notification of the channel, failing the test if there is none.
+func findNotification(t *testing.T, store *repository.Memory, channel string) models.Notification {
+	t.Helper()
+
+	for _, notification := range store.Notifications() {
+		if notification.Channel == channel {
+			return notification
+		}
+	}
+
+	t.Fatalf("no %s notification stored", channel)
+	return models.Notification{}
+}
+
+// Boots the router with the Event Grid webhook enabled.
+func newWebhookRouter(t *testing.T) (*gin.Engine, *repository.Memory, *acstest.Server) {
+	t.Helper()
+
+	return newTestRouter(t, func(cfg *config.Config, client *models.Client) {
+		cfg.WebhookToken = testWebhookToken
+	})
+}
+
+// Posts a batch of Event Grid events to the webhook.
+func postEvents(router *gin.Engine, query, body string) *httptest.ResponseRecorder {
+	request := httptest.NewRequest(http.MethodPost, "/api/v1/webhooks/eventgrid"+query, strings.NewReader(body))
+	request.Header.Set("Content-Type", "application/json")
+
+	response := httptest.NewRecorder()
+	router.ServeHTTP(response, request)
+
+	return response
+}
@@ -0,0 +1,72 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"strings"
+)
+
+type contextKey struct{}
+
+// Wraps a slog handler to add the request ID stored in the context to every record.
+type requestIDHandler struct {
+	slog.Handler
+}
+
+// Adds the request_id attribute when the record is logged with a request context.
+func (h requestIDHandler) Handle(ctx context.Context, record slog.Record) error {
+	if id := RequestID(ctx); id != "" {
+		record.AddAttrs(slog.String("request_id", id))
+	}
+
+	return h.Handler.Handle(ctx, record)
+}
+
+func (h requestIDHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return requestIDHandler{h.Handler.WithAttrs(attrs)}
+}
+
+func (h requestIDHandler) WithGroup(name string) slog.Handler {
+	return requestIDHandler{h.Handler.WithGroup(name)}
+}
+
+// Configures the default slog logger to write JSON lines to stdout at the given level (debug, info, warn, error).
+// Also redirects the standard log package, so every log line shares the same format.
+// Called once at startup.
+func Init(level string) {
+	var logLevel slog.Level
+	if err := logLevel.UnmarshalText([]byte(strings.ToUpper(level))); err != nil {
+		logLevel = slog.LevelInfo
+	}
+
+	handler := slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{Level: logLevel})
+
+	slog.SetDefault(slog.New(requestIDHandler{handler}))
+}
+
+// Returns a copy of the context carrying the request ID.
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, contextKey{}, id)
+}
+
+// Returns the request ID stored in the context, or an empty string if there is none.
+func RequestID(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+
+	id, _ := ctx.Value(contextKey{}).(string)
+
+	return id
+}
+
+// Returns the default logger with the context's request ID attached.
+// Used by code that logs without passing the context to every call (e.g. per-request services).
+func FromContext(ctx context.Context) *slog.Logger {
+	if id := RequestID(ctx); id != "" {
+		return slog.Default().With("request_id", id)
+	}
+
+	return slog.Default()
+}
@@ -0,0 +1,44 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"testing"
+)
+
+// Checks if records logged with a request context carry the request ID.
+func TestRequestIDHandler(t *testing.T) {
+	var buffer bytes.Buffer
+	log := slog.New(requestIDHandler{slog.NewJSONHandler(&buffer, nil)})
+
+	tests := []struct {
+		name string
+		ctx  context.Context
+		want string
+	}{
+		{"With request ID", WithRequestID(context.Background(), "abc-123"), "abc-123"},
+		{"Without request ID", context.Background(), ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buffer.Reset()
+			log.InfoContext(tt.ctx, "message", "key", "value")
+
+			var record map[string]any
+			if err := json.Unmarshal(buffer.Bytes(), &record); err != nil {
+				t.Fatalf("log line is not JSON: %v", err)
+			}
+
+			got, _ := record["request_id"].(string)
+			if got != tt.want {
+				t.Errorf("request_id = %q, want %q", got, tt.want)
+			}
+			if record["key"] != "value" {
+				t.Errorf("key = %v, want %q", record["key"], "value")
+			}
+		})
+	}
+}
@@ -0,0 +1,137 @@
+package metrics
+
+import (
+	"errors"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Lead outcomes, used as the "outcome" label of LeadsTotal.
+const (
+	LeadSent          = "sent"           // Both notifications were sent.
+	LeadPartial       = "partial"        // Only one of the notifications was sent.
+	LeadFailed        = "failed"         // No notification could be sent.
+	LeadSuppressed    = "suppressed"     // No notification was sent because the client's contacts are suppressed.
+	LeadInvalid       = "invalid"        // The lead failed validation.
+	LeadRateLimited   = "rate_limited"   // The lead was rejected by a client or submitter rate limit.
+	LeadQuotaExceeded = "quota_exceeded" // The lead was rejected by the client's monthly quota.
+	LeadUnverified    = "unverified"     // The lead was rejected because none of the client's contacts is verified.
+)
+
+// Notification results, used as the "result" label of NotificationsTotal.
+const (
+	NotificationSuccess    = "success"    // The provider accepted the notification.
+	NotificationFailure    = "failure"    // The provider call failed.
+	NotificationSkipped    = "skipped"    // The notification was not sent (e.g. quota exhausted).
+	NotificationSuppressed = "suppressed" // The notification was not sent because the recipient is on the suppression list.
+)
+
+var (
+	// Number of HTTP requests by route, method and status code.
+	HTTPRequestsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "http_requests_total",
+		Help: "Number of HTTP requests by route, method and status code.",
+	}, []string{"route", "method", "status"})
+
+	// Latency of HTTP requests by route, method and status code.
+	HTTPRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "http_request_duration_seconds",
+		Help:    "Latency of HTTP requests by route, method and status code.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"route", "method", "status"})
+
+	// Number of submitted leads by client and outcome.
+	LeadsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "leads_total",
+		Help: "Number of submitted leads by client and outcome.",
+	}, []string{"client", "outcome"})
+
+	// Number of notifications by channel, provider and result.
+	NotificationsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "notifications_total",
+		Help: "Number of notifications by channel, provider and result.",
+	}, []string{"channel", "provider", "result"})
+
+	// Latency of Azure Communication Services API calls by operation and status code.
+	AzureRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "azure_request_duration_seconds",
+		Help:    "Latency of Azure Communication Services API calls by operation and status code.",
+		Buckets: []float64{0.05, 0.1, 0.25, 0.5, 1, 2, 4, 8, 16},
+	}, []string{"operation", "status"})
+
+	// Number of delivery reports received from Event Grid by channel and notification status.
+	DeliveryReportsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "delivery_reports_total",
+		Help: "Number of delivery reports received from Event Grid by channel and status (delivered, bounced, failed, unknown).",
+	}, []string{"channel", "status"})
+
+	// Number of inbound SMS received from Event Grid by kind.
+	InboundSMSTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "inbound_sms_total",
+		Help: "Number of inbound SMS received from Event Grid by kind (opt_out, opt_in, help, reply, unmatched).",
+	}, []string{"kind"})
+
+	// Number of requests rejected by rate limits, by layer.
+	RateLimitRejectionsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "rate_limit_rejections_total",
+		Help: "Number of requests rejected by rate limits, by layer (ip, client, email, phone).",
+	}, []string{"layer"})
+)
+
+// Returns the handler serving all registered metrics in the Prometheus exposition format.
+func Handler() gin.HandlerFunc {
+	return gin.WrapH(promhttp.Handler())
+}
+
+// Records the count and latency of every HTTP request.
+// Requests that match no route are grouped under "unmatched" to keep the label cardinality bounded.
+func Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+
+		status := strconv.Itoa(c.Writer.Status())
+
+		HTTPRequestsTotal.WithLabelValues(route, c.Request.Method, status).Inc()
+		HTTPRequestDuration.WithLabelValues(route, c.Request.Method, status).Observe(time.Since(start).Seconds())
+	}
+}
+
+// Records a notification attempt for the channel (email, sms) with the result derived from the send error.
+// Errors matching one of skipped (e.g. an exhausted quota, even wrapped) are recorded as skipped rather than failed.
+func ObserveNotification(channel string, err error, skipped ...error) {
+	result := NotificationSuccess
+	isSkipped := func(target error) bool { return errors.Is(err, target) }
+
+	switch {
+	case err != nil && slices.ContainsFunc(skipped, isSkipped):
+		result = NotificationSkipped
+	case err != nil:
+		result = NotificationFailure
+	}
+
+	NotificationsTotal.WithLabelValues(channel, "azure", result).Inc()
+}
+
+// Records a notification that was not sent because its recipient is on the suppression list.
+func ObserveSuppressed(channel string) {
+	NotificationsTotal.WithLabelValues(channel, "azure", NotificationSuppressed).Inc()
+}
+
+// Records the latency of an Azure API call for the operation (email, sms) with the response status code.
+// A status of 0 means the request failed before a response was received.
+func ObserveAzure(operation string, start time.Time, status int) {
+	AzureRequestDuration.WithLabelValues(operation, strconv.Itoa(status)).Observe(time.Since(start).Seconds())
+}
@@ -0,0 +1,40 @@
+package metrics
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+// Checks if notification attempts are counted under the result derived from the send error.
+func TestObserveNotification(t *testing.T) {
+	errSkipped := errors.New("skipped")
+
+	tests := []struct {
+		name    string
+		err     error
+		skipped []error
+		result  string
+	}{
+		{"Sent", nil, []error{errSkipped}, NotificationSuccess},
+		{"Failed", errors.New("provider error"), []error{errSkipped}, NotificationFailure},
+		{"Skipped", errSkipped, []error{errSkipped}, NotificationSkipped},
+		{"Wrapped skip", fmt.Errorf("email: %w", errSkipped), []error{errSkipped}, NotificationSkipped},
+		{"Skip not listed", errSkipped, nil, NotificationFailure},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			counter := NotificationsTotal.WithLabelValues("test", "azure", tt.result)
+			before := testutil.ToFloat64(counter)
+
+			ObserveNotification("test", tt.err, tt.skipped...)
+
+			if got := testutil.ToFloat64(counter) - before; got != 1 {
+				t.Errorf("%s notifications increased by %v, want 1", tt.result, got)
+			}
+		})
+	}
+}
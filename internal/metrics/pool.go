@@ -0,0 +1,60 @@
+package metrics
+
+import (
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Exposes pgxpool statistics, read from the pool on every scrape.
+type poolCollector struct {
+	pool *pgxpool.Pool
+
+	acquired        *prometheus.Desc
+	idle            *prometheus.Desc
+	total           *prometheus.Desc
+	max             *prometheus.Desc
+	acquireCount    *prometheus.Desc
+	waitCount       *prometheus.Desc
+	acquireDuration *prometheus.Desc
+	waitDuration    *prometheus.Desc
+}
+
+// Registers the collector of the pool's statistics with the default registry.
+// Called once at startup with the application's database pool.
+func RegisterPool(pool *pgxpool.Pool) error {
+	return prometheus.Register(&poolCollector{
+		pool:            pool,
+		acquired:        prometheus.NewDesc("pgxpool_acquired_connections", "Number of connections currently acquired from the pool.", nil, nil),
+		idle:            prometheus.NewDesc("pgxpool_idle_connections", "Number of idle connections in the pool.", nil, nil),
+		total:           prometheus.NewDesc("pgxpool_total_connections", "Total number of connections in the pool.", nil, nil),
+		max:             prometheus.NewDesc("pgxpool_max_connections", "Maximum size of the pool.", nil, nil),
+		acquireCount:    prometheus.NewDesc("pgxpool_acquire_total", "Number of successful connection acquisitions.", nil, nil),
+		waitCount:       prometheus.NewDesc("pgxpool_empty_acquire_total", "Number of acquisitions that had to wait for a connection.", nil, nil),
+		acquireDuration: prometheus.NewDesc("pgxpool_acquire_duration_seconds_total", "Total time spent acquiring connections.", nil, nil),
+		waitDuration:    prometheus.NewDesc("pgxpool_empty_acquire_wait_seconds_total", "Total time spent waiting for a connection when the pool was empty.", nil, nil),
+	})
+}
+
+func (p *poolCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- p.acquired
+	ch <- p.idle
+	ch <- p.total
+	ch <- p.max
+	ch <- p.acquireCount
+	ch <- p.waitCount
+	ch <- p.acquireDuration
+	ch <- p.waitDuration
+}
+
+func (p *poolCollector) Collect(ch chan<- prometheus.Metric) {
+	stat := p.pool.Stat()
+
+	ch <- prometheus.MustNewConstMetric(p.acquired, prometheus.GaugeValue, float64(stat.AcquiredConns()))
+	ch <- prometheus.MustNewConstMetric(p.idle, prometheus.GaugeValue, float64(stat.IdleConns()))
+	ch <- prometheus.MustNewConstMetric(p.total, prometheus.GaugeValue, float64(stat.TotalConns()))
+	ch <- prometheus.MustNewConstMetric(p.max, prometheus.GaugeValue, float64(stat.MaxConns()))
+	ch <- prometheus.MustNewConstMetric(p.acquireCount, prometheus.CounterValue, float64(stat.AcquireCount()))
+	ch <- prometheus.MustNewConstMetric(p.waitCount, prometheus.CounterValue, float64(stat.EmptyAcquireCount()))
+	ch <- prometheus.MustNewConstMetric(p.acquireDuration, prometheus.CounterValue, stat.AcquireDuration().Seconds())
+	ch <- prometheus.MustNewConstMetric(p.waitDuration, prometheus.CounterValue, stat.EmptyAcquireWaitTime().Seconds())
+}
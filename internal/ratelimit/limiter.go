@@ -0,0 +1,74 @@
+package ratelimit
+
+import (
+	"context"
+	"time"
+)
+
+// Token bucket settings for a single rate limit.
+// The bucket holds up to Burst tokens and regains one token every Interval.
+type Limit struct {
+	Burst    int           // Maximum number of requests allowed at once.
+	Interval time.Duration // Time needed to regain a single request.
+}
+
+// Token bucket of a single key (e.g. client IP) with its limit.
+type Bucket struct {
+	Key   string
+	Limit Limit
+}
+
+// Outcome of a rate limit check.
+// Used by the middleware to decide whether to continue and to populate rate limit headers.
+type Result struct {
+	Key        string        // Key of the bucket that decided the outcome (the exhausted one, or the one with the fewest requests left).
+	Allowed    bool          // Whether the request may proceed.
+	Limit      int           // Bucket capacity (Limit.Burst).
+	Remaining  int           // Requests left in the bucket after this one.
+	RetryAfter time.Duration // Time until the next request is allowed (zero when allowed).
+	Reset      time.Duration // Time until the bucket is full again.
+}
+
+// Checks and consumes rate limit budget for one or more buckets at once.
+// A token is consumed from every bucket only if all of them have one, so a rejected request never
+// spends the budget of the other buckets. Implementations must be safe for concurrent use.
+type Limiter interface {
+	Allow(ctx context.Context, buckets ...Bucket) (Result, error)
+}
+
+// Builds the result of a token bucket check from the tokens left after the request.
+func newResult(key string, allowed bool, tokens float64, limit Limit) Result {
+	result := Result{
+		Key:       key,
+		Allowed:   allowed,
+		Limit:     limit.Burst,
+		Remaining: max(int(tokens), 0),
+		Reset:     time.Duration((float64(limit.Burst) - tokens) * float64(limit.Interval)),
+	}
+
+	if !allowed {
+		result.RetryAfter = time.Duration((1 - tokens) * float64(limit.Interval))
+	}
+
+	return result
+}
+
+// Decides the outcome of a check from the tokens available in each bucket (before consuming any).
+// Returns the tokens left in each bucket if the request is allowed, or nil if any bucket is exhausted.
+func take(buckets []Bucket, tokens []float64) (Result, []float64) {
+	var result Result
+	left := make([]float64, len(tokens))
+
+	for i, bucket := range buckets {
+		if tokens[i] < 1 {
+			return newResult(bucket.Key, false, tokens[i], bucket.Limit), nil
+		}
+
+		left[i] = tokens[i] - 1
+		if current := newResult(bucket.Key, true, left[i], bucket.Limit); i == 0 || current.Remaining < result.Remaining {
+			result = current
+		}
+	}
+
+	return result, left
+}
@@ -0,0 +1,67 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/patrickmn/go-cache"
+)
+
+// In-process token bucket limiter.
+// Fast and dependency-free, but each replica keeps its own budget and restarts reset it.
+type Memory struct {
+	cache *cache.Cache
+	mutex sync.Mutex
+	now   func() time.Time // Clock used to refill the buckets; replaced in tests.
+}
+
+// In-memory state of a single token bucket.
+type bucket struct {
+	tokens    float64
+	updatedAt time.Time
+}
+
+// Creates an in-memory limiter; idle buckets are evicted once they would be full again.
+func NewMemory() *Memory {
+	return &Memory{cache: cache.New(time.Minute, 5*time.Minute), now: time.Now}
+}
+
+// Refills the buckets based on elapsed time and consumes one token from each if all have one available.
+func (m *Memory) Allow(ctx context.Context, buckets ...Bucket) (Result, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	now := m.now()
+	tokens := make([]float64, len(buckets))
+
+	for i, b := range buckets {
+		tokens[i] = float64(b.Limit.Burst)
+
+		if value, found := m.cache.Get(b.Key); found {
+			previous := value.(*bucket)
+			tokens[i] = refill(previous.tokens, now.Sub(previous.updatedAt), b.Limit)
+		}
+	}
+
+	result, left := take(buckets, tokens)
+	if left == nil {
+		return result, nil
+	}
+
+	for i, b := range buckets {
+		reset := newResult(b.Key, true, left[i], b.Limit).Reset
+		m.cache.Set(b.Key, &bucket{tokens: left[i], updatedAt: now}, max(reset, time.Second))
+	}
+
+	return result, nil
+}
+
+// Adds the tokens regained over the elapsed time, capped at the bucket capacity.
+func refill(tokens float64, elapsed time.Duration, limit Limit) float64 {
+	if limit.Interval > 0 {
+		tokens += float64(elapsed) / float64(limit.Interval)
+	}
+
+	return min(tokens, float64(limit.Burst))
+}
@@ -0,0 +1,110 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// Checks if the in-memory limiter allows the burst, then rejects until a token is regained.
+func TestMemoryAllow(t *testing.T) {
+	limiter := NewMemory()
+	clock := time.Date(2025, 6, 15, 12, 0, 0, 0, time.UTC)
+	limiter.now = func() time.Time { return clock }
+	limit := Limit{Burst: 3, Interval: time.Minute}
+	ctx := context.Background()
+
+	for i := range limit.Burst {
+		result, err := limiter.Allow(ctx, Bucket{Key: "ip:203.0.113.1", Limit: limit})
+		if err != nil {
+			t.Fatalf("Allow() error = %v", err)
+		}
+		if !result.Allowed {
+			t.Fatalf("request %d was rejected within the burst", i+1)
+		}
+		if want := limit.Burst - i - 1; result.Remaining != want {
+			t.Errorf("request %d Remaining = %d, want %d", i+1, result.Remaining, want)
+		}
+	}
+
+	result, _ := limiter.Allow(ctx, Bucket{Key: "ip:203.0.113.1", Limit: limit})
+	if result.Allowed {
+		t.Fatalf("request over the burst was allowed")
+	}
+	if result.RetryAfter <= 0 || result.RetryAfter > limit.Interval {
+		t.Errorf("RetryAfter = %v, want within (0, %v]", result.RetryAfter, limit.Interval)
+	}
+	if result.Limit != limit.Burst {
+		t.Errorf("Limit = %d, want %d", result.Limit, limit.Burst)
+	}
+
+	other, _ := limiter.Allow(ctx, Bucket{Key: "ip:203.0.113.2", Limit: limit})
+	if !other.Allowed {
+		t.Errorf("a different key shares the exhausted budget")
+	}
+
+	clock = clock.Add(limit.Interval)
+
+	result, _ = limiter.Allow(ctx, Bucket{Key: "ip:203.0.113.1", Limit: limit})
+	if !result.Allowed {
+		t.Errorf("request was rejected after a token was regained")
+	}
+}
+
+// Checks if a request spanning several buckets consumes nothing unless every bucket allows it.
+func TestMemoryAllowBuckets(t *testing.T) {
+	limiter := NewMemory()
+	ctx := context.Background()
+	email := Bucket{Key: "email:john@example.com", Limit: Limit{Burst: 2, Interval: time.Hour}}
+	phone := Bucket{Key: "phone:+12025550123", Limit: Limit{Burst: 1, Interval: time.Hour}}
+
+	tests := []struct {
+		name      string
+		buckets   []Bucket
+		allowed   bool
+		key       string // Key of the deciding bucket.
+		remaining int
+	}{
+		{"Both allowed", []Bucket{email, phone}, true, phone.Key, 0},
+		{"Phone exhausted", []Bucket{email, phone}, false, phone.Key, 0},
+		{"Email budget kept", []Bucket{email}, true, email.Key, 0},
+		{"Email exhausted", []Bucket{email}, false, email.Key, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := limiter.Allow(ctx, tt.buckets...)
+			if err != nil {
+				t.Fatalf("Allow() error = %v", err)
+			}
+			if result.Allowed != tt.allowed || result.Key != tt.key || result.Remaining != tt.remaining {
+				t.Errorf("Allow() = %+v, want allowed %v by %q with %d remaining", result, tt.allowed, tt.key, tt.remaining)
+			}
+		})
+	}
+}
+
+// Checks if regained tokens never exceed the bucket capacity.
+func TestRefill(t *testing.T) {
+	limit := Limit{Burst: 5, Interval: time.Second}
+
+	tests := []struct {
+		name    string
+		tokens  float64
+		elapsed time.Duration
+		want    float64
+	}{
+		{"No time elapsed", 2, 0, 2},
+		{"Partial token", 0, 500 * time.Millisecond, 0.5},
+		{"Several tokens", 1, 3 * time.Second, 4},
+		{"Capped at burst", 4, time.Hour, 5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := refill(tt.tokens, tt.elapsed, limit); got != tt.want {
+				t.Errorf("refill(%v, %v) = %v, want %v", tt.tokens, tt.elapsed, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,87 @@
+package ratelimit
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Token bucket limiter stored in the "rate_limits" table.
+// Shares the budget between all replicas and survives restarts.
+type Postgres struct {
+	Pool *pgxpool.Pool
+}
+
+// Creates a Postgres-backed limiter using the existing connection pool.
+func NewPostgres(db *pgxpool.Pool) *Postgres {
+	return &Postgres{Pool: db}
+}
+
+// Refills the buckets and consumes one token from each if all have one available, in a single transaction.
+// The rows are locked (in key order, to avoid deadlocks) for the duration of the check, so concurrent requests
+// from all replicas are serialized.
+func (p *Postgres) Allow(ctx context.Context, buckets ...Bucket) (Result, error) {
+	buckets = slices.Clone(buckets)
+	slices.SortFunc(buckets, func(a, b Bucket) int { return strings.Compare(a.Key, b.Key) })
+
+	tx, err := p.Pool.Begin(ctx)
+	if err != nil {
+		return Result{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	tokens := make([]float64, len(buckets))
+
+	for i, bucket := range buckets {
+		var elapsed float64
+
+		err = tx.QueryRow(
+			ctx,
+			`insert into "rate_limits" ("key", "tokens", "updated_at", "expires_at") values ($1, $2, now(), now())
+			on conflict ("key") do update set "key" = excluded."key"
+			returning "tokens", extract(epoch from now() - "updated_at")::float8`,
+			bucket.Key,
+			bucket.Limit.Burst,
+		).Scan(&tokens[i], &elapsed)
+		if err != nil {
+			return Result{}, err
+		}
+
+		tokens[i] = refill(tokens[i], time.Duration(elapsed*float64(time.Second)), bucket.Limit)
+	}
+
+	result, left := take(buckets, tokens)
+	if left == nil {
+		return result, nil
+	}
+
+	for i, bucket := range buckets {
+		_, err = tx.Exec(
+			ctx,
+			`update "rate_limits" set "tokens" = $2, "updated_at" = now(), "expires_at" = now() + make_interval(secs => $3) where "key" = $1`,
+			bucket.Key,
+			left[i],
+			newResult(bucket.Key, true, left[i], bucket.Limit).Reset.Seconds(),
+		)
+		if err != nil {
+			return Result{}, err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return Result{}, err
+	}
+
+	return result, nil
+}
+
+// Deletes buckets that are full again, since a missing row is equivalent to a full bucket.
+// Called periodically to keep the table small.
+func (p *Postgres) Cleanup(ctx context.Context) error {
+	_, err := p.Pool.Exec(ctx, `delete from "rate_limits" where "expires_at" < now()`)
+
+	return err
+}
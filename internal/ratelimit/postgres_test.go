@@ -0,0 +1,104 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"communications/internal/dbtest"
+)
+
+// Checks if the Postgres limiter allows the burst, rejects until a token is regained, and consumes nothing
+// from a request spanning several buckets unless every bucket allows it.
+// Runs against a throwaway schema of the database at TEST_DATABASE_URL (see dbtest).
+func TestPostgresAllow(t *testing.T) {
+	pool, _ := dbtest.NewSchema(t)
+	limiter := NewPostgres(pool)
+	ctx := context.Background()
+
+	email := Bucket{Key: "email:john@example.com", Limit: Limit{Burst: 2, Interval: time.Hour}}
+	phone := Bucket{Key: "phone:+12025550123", Limit: Limit{Burst: 1, Interval: time.Hour}}
+
+	tests := []struct {
+		name      string
+		buckets   []Bucket
+		elapsed   time.Duration // Time moved back on the stored buckets before the request.
+		allowed   bool
+		key       string // Key of the deciding bucket.
+		remaining int
+	}{
+		{"Both allowed", []Bucket{email, phone}, 0, true, phone.Key, 0},
+		{"Phone exhausted", []Bucket{phone, email}, 0, false, phone.Key, 0},
+		{"Email budget kept", []Bucket{email}, 0, true, email.Key, 0},
+		{"Email exhausted", []Bucket{email}, 0, false, email.Key, 0},
+		{"Token regained", []Bucket{phone, email}, time.Hour, true, email.Key, 0},
+		{"Regained tokens consumed", []Bucket{email}, 0, false, email.Key, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.elapsed > 0 {
+				_, err := pool.Exec(ctx, `update "rate_limits" set "updated_at" = "updated_at" - make_interval(secs => $1)`, tt.elapsed.Seconds())
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			result, err := limiter.Allow(ctx, tt.buckets...)
+			if err != nil {
+				t.Fatalf("Allow() error = %v", err)
+			}
+			if result.Allowed != tt.allowed || result.Key != tt.key || result.Remaining != tt.remaining {
+				t.Errorf("Allow() = %+v, want allowed %v by %q with %d remaining", result, tt.allowed, tt.key, tt.remaining)
+			}
+		})
+	}
+}
+
+// Checks if concurrent requests never overspend a bucket, and requests listing the same buckets
+// in opposite orders do not deadlock, since the rows are locked in key order.
+func TestPostgresAllowConcurrent(t *testing.T) {
+	pool, _ := dbtest.NewSchema(t)
+	limiter := NewPostgres(pool)
+	ctx := context.Background()
+
+	limit := Limit{Burst: 10, Interval: time.Hour}
+	first := Bucket{Key: "ip:203.0.113.1", Limit: limit}
+	second := Bucket{Key: "ip:203.0.113.2", Limit: limit}
+
+	var wg sync.WaitGroup
+	var mutex sync.Mutex
+	allowed := 0
+
+	for i := range 3 * limit.Burst {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			buckets := []Bucket{first, second}
+			if i%2 == 1 {
+				buckets = []Bucket{second, first}
+			}
+
+			result, err := limiter.Allow(ctx, buckets...)
+			if err != nil {
+				t.Errorf("Allow() error = %v", err)
+				return
+			}
+
+			if result.Allowed {
+				mutex.Lock()
+				allowed++
+				mutex.Unlock()
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	if allowed != limit.Burst {
+		t.Errorf("allowed %d requests, want %d", allowed, limit.Burst)
+	}
+}
@@ -0,0 +1,608 @@
+package repository
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"communications/internal/database"
+	"communications/internal/database/models"
+)
+
+// Repositories kept in memory.
+// Used by handler tests instead of a database; not meant for production.
+type Memory struct {
+	mutex   sync.Mutex
+	clients []models.Client
+	leads   []models.Lead
+	usage   map[string]models.Usage // Keyed by client ID and period.
+
+	notifications []models.Notification
+	suppressions  []models.Suppression
+	suppressionID int // Last assigned suppression ID, since suppressions can be removed.
+	replies       []models.Reply
+	inbound       []string // Message IDs of the processed inbound SMS.
+}
+
+// Ensures Memory implements every repository.
+var _ Store = (*Memory)(nil)
+
+// Creates empty in-memory repositories.
+func NewMemory() *Memory {
+	return &Memory{usage: map[string]models.Usage{}}
+}
+
+// Returns the stored leads in insertion order.
+// Used by tests to assert what a handler stored.
+func (m *Memory) Leads() []models.Lead {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	return slices.Clone(m.leads)
+}
+
+// Returns the stored notifications in insertion order.
+// Used by tests to assert which notifications a handler recorded and how reports updated them.
+func (m *Memory) Notifications() []models.Notification {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	return slices.Clone(m.notifications)
+}
+
+// Returns the stored replies in insertion order.
+// Used by tests to assert how inbound SMS were linked and forwarded.
+func (m *Memory) Replies() []models.Reply {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	return slices.Clone(m.replies)
+}
+
+// Always succeeds, since the repositories live in memory.
+func (m *Memory) Ping(ctx context.Context) error {
+	return nil
+}
+
+// Returns the latest embedded migration version, since in-memory repositories always match the models.
+func (m *Memory) MigrationVersion(ctx context.Context) (uint, bool, error) {
+	version, err := database.LatestVersion()
+
+	return version, false, err
+}
+
+// Finds a client that has not been deleted by ID, or returns ErrNotFound.
+func (m *Memory) FindClient(ctx context.Context, id string) (models.Client, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	for _, client := range m.clients {
+		if client.ID == id && client.DeletedAt == nil {
+			return client, nil
+		}
+	}
+
+	return models.Client{}, ErrNotFound
+}
+
+// Returns all clients that have not been deleted, oldest first.
+func (m *Memory) ListClients(ctx context.Context) ([]models.Client, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	clients := []models.Client{}
+	for _, client := range m.clients {
+		if client.DeletedAt == nil {
+			clients = append(clients, client)
+		}
+	}
+
+	return clients, nil
+}
+
+// Stores a new client and sets its timestamps.
+func (m *Memory) CreateClient(ctx context.Context, client *models.Client) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	now := time.Now().UTC()
+	client.CreatedAt, client.UpdatedAt = now, now
+	m.clients = append(m.clients, *client)
+
+	return nil
+}
+
+// Soft-deletes a client, keeping its leads, or returns ErrNotFound.
+func (m *Memory) DeleteClient(ctx context.Context, id string) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	for i := range m.clients {
+		if m.clients[i].ID == id && m.clients[i].DeletedAt == nil {
+			now := time.Now().UTC()
+			m.clients[i].DeletedAt, m.clients[i].UpdatedAt = &now, now
+			return nil
+		}
+	}
+
+	return ErrNotFound
+}
+
+// Marks the client's email as verified if it still matches, or returns ErrNotFound.
+func (m *Memory) VerifyEmail(ctx context.Context, id, email string) error {
+	return m.updateClient(id, func(client *models.Client) bool {
+		if client.Email != email {
+			return false
+		}
+
+		client.EmailVerified = true
+		client.Verified = client.PhoneVerified
+		return true
+	})
+}
+
+// Stores the hash of a new phone verification code, replacing the pending one and its attempts.
+func (m *Memory) SetPhoneCode(ctx context.Context, id, code string, expiresAt time.Time) error {
+	return m.updateClient(id, func(client *models.Client) bool {
+		client.PhoneCode, client.PhoneCodeExpiresAt, client.PhoneCodeAttempts = &code, &expiresAt, 0
+		return true
+	})
+}
+
+// Counts an attempt to enter the pending phone verification code and returns the attempts so far.
+func (m *Memory) AddPhoneCodeAttempt(ctx context.Context, id string) (int, error) {
+	attempts := 0
+
+	err := m.updateClient(id, func(client *models.Client) bool {
+		client.PhoneCodeAttempts++
+		attempts = client.PhoneCodeAttempts
+		return true
+	})
+
+	return attempts, err
+}
+
+// Marks the client's phone as verified if it still matches and clears the pending code, or returns ErrNotFound.
+func (m *Memory) VerifyPhone(ctx context.Context, id, phone string) error {
+	return m.updateClient(id, func(client *models.Client) bool {
+		if client.Phone != phone {
+			return false
+		}
+
+		client.PhoneVerified = true
+		client.Verified = client.EmailVerified
+		client.PhoneCode, client.PhoneCodeExpiresAt, client.PhoneCodeAttempts = nil, nil, 0
+		return true
+	})
+}
+
+// Applies update to a client that has not been deleted, returning ErrNotFound if it is missing
+// or update reports that it does not match.
+func (m *Memory) updateClient(id string, update func(client *models.Client) bool) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	for i := range m.clients {
+		if m.clients[i].ID == id && m.clients[i].DeletedAt == nil {
+			if !update(&m.clients[i]) {
+				return ErrNotFound
+			}
+
+			m.clients[i].UpdatedAt = time.Now().UTC()
+			return nil
+		}
+	}
+
+	return ErrNotFound
+}
+
+// Finds a lead by ID, or returns ErrNotFound.
+func (m *Memory) FindLead(ctx context.Context, id int) (models.Lead, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	for _, lead := range m.leads {
+		if lead.ID == id {
+			return lead, nil
+		}
+	}
+
+	return models.Lead{}, ErrNotFound
+}
+
+// Stores a new lead and sets its ID and submission time.
+func (m *Memory) CreateLead(ctx context.Context, lead *models.Lead) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	lead.ID = len(m.leads) + 1
+	lead.Datetime = time.Now().UTC()
+	m.leads = append(m.leads, *lead)
+
+	return nil
+}
+
+// Passes the leads matching the filter to fn, oldest first, and stops at the first error.
+func (m *Memory) ExportLeads(ctx context.Context, filter LeadFilter, fn func(models.Lead) error) error {
+	for _, lead := range m.Leads() {
+		if !filter.matches(lead) {
+			continue
+		}
+		if err := fn(lead); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Returns the client's counters for the current billing period.
+func (m *Memory) CurrentUsage(ctx context.Context, clientID string) (models.Usage, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	current := period(time.Now())
+	usage := m.usage[usageKey(clientID, current)]
+	usage.ClientID, usage.Period = clientID, current
+
+	return usage, nil
+}
+
+// Adds sent notifications to the client's counters for the current billing period.
+func (m *Memory) AddUsage(ctx context.Context, clientID string, emails, sms int) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	key := usageKey(clientID, period(time.Now()))
+	usage := m.usage[key]
+	usage.Emails += emails
+	usage.SMS += sms
+	m.usage[key] = usage
+
+	return nil
+}
+
+// Adds notifications to the client's counters for the current billing period only if the counters stay within
+// the quotas (nil means unlimited). Returns false, without changing the counters, if a quota would be exceeded.
+func (m *Memory) ReserveUsage(ctx context.Context, clientID string, emails, sms int, emailQuota, smsQuota *int) (bool, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	key := usageKey(clientID, period(time.Now()))
+	usage := m.usage[key]
+
+	if !withinQuota(usage.Emails, emails, emailQuota) || !withinQuota(usage.SMS, sms, smsQuota) {
+		return false, nil
+	}
+
+	usage.Emails += emails
+	usage.SMS += sms
+	m.usage[key] = usage
+
+	return true, nil
+}
+
+// Reports whether adding count notifications to used keeps the counter within the quota (nil means unlimited).
+func withinQuota(used, count int, quota *int) bool {
+	return count == 0 || quota == nil || used+count <= *quota
+}
+
+// Returns the usage of every client during the billing period that contains the month.
+func (m *Memory) ListUsage(ctx context.Context, month time.Time) ([]models.Usage, error) {
+	clients, _ := m.ListClients(ctx)
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	report := []models.Usage{}
+	billing := period(month)
+
+	for _, client := range clients {
+		usage := m.usage[usageKey(client.ID, billing)]
+		usage.ClientID, usage.ClientName, usage.Period = client.ID, client.Name, billing
+		usage.EmailQuota, usage.SMSQuota = client.EmailQuota, client.SMSQuota
+		report = append(report, usage)
+	}
+
+	slices.SortFunc(report, func(a, b models.Usage) int { return strings.Compare(a.ClientName, b.ClientName) })
+
+	return report, nil
+}
+
+// Stores a notification and sets its ID and timestamps (keeping a creation time set by tests).
+// The status defaults to accepted (sent).
+func (m *Memory) CreateNotification(ctx context.Context, notification *models.Notification) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	now := time.Now().UTC()
+	notification.ID = len(m.notifications) + 1
+	if notification.Status == "" {
+		notification.Status = models.NotificationAccepted
+	}
+	notification.UpdatedAt = now
+	if notification.CreatedAt.IsZero() {
+		notification.CreatedAt = now
+	}
+	m.notifications = append(m.notifications, *notification)
+
+	return nil
+}
+
+// Links stored notifications to the lead they were sent for.
+func (m *Memory) LinkNotifications(ctx context.Context, leadID int, ids []int) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	for i := range m.notifications {
+		if slices.Contains(ids, m.notifications[i].ID) {
+			m.notifications[i].LeadID = &leadID
+			m.notifications[i].UpdatedAt = time.Now().UTC()
+		}
+	}
+
+	return nil
+}
+
+// Sets the delivery status of the notification with the provider's message ID, or returns ErrNotFound.
+func (m *Memory) UpdateNotificationStatus(ctx context.Context, channel, messageID, status string, reason *string) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	for i := range m.notifications {
+		if m.notifications[i].Channel == channel && m.notifications[i].MessageID != nil && *m.notifications[i].MessageID == messageID {
+			m.notifications[i].Status, m.notifications[i].Reason = status, reason
+			m.notifications[i].UpdatedAt = time.Now().UTC()
+			return nil
+		}
+	}
+
+	return ErrNotFound
+}
+
+// Returns up to limit notifications sent to the recipient since the given time that are linked to a lead
+// (ignoring suppressed ones, which were never sent), latest first.
+func (m *Memory) RecentNotifications(ctx context.Context, channel, recipient string, since time.Time, limit int) ([]models.Notification, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	recent := []models.Notification{}
+	for _, notification := range slices.Backward(m.notifications) {
+		if len(recent) == limit {
+			break
+		}
+		if notification.Channel == channel && notification.Recipient == recipient && notification.MessageID != nil && notification.LeadID != nil && !notification.CreatedAt.Before(since) {
+			recent = append(recent, notification)
+		}
+	}
+
+	return recent, nil
+}
+
+// Claims up to limit email notifications created since the given time whose Azure operation has not finished
+// and was not checked since checkedBefore, least recently checked first, and records them as checked now.
+func (m *Memory) ClaimOperations(ctx context.Context, since, checkedBefore time.Time, limit int) ([]models.Notification, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	var pending []*models.Notification
+	for i := range m.notifications {
+		notification := &m.notifications[i]
+		if notification.Channel != "email" || notification.MessageID == nil || notification.OperationStatus != nil || notification.CreatedAt.Before(since) {
+			continue
+		}
+		if notification.CheckedAt == nil || notification.CheckedAt.Before(checkedBefore) {
+			pending = append(pending, notification)
+		}
+	}
+
+	slices.SortStableFunc(pending, func(a, b *models.Notification) int {
+		switch {
+		case a.CheckedAt == nil && b.CheckedAt == nil:
+			return a.CreatedAt.Compare(b.CreatedAt)
+		case a.CheckedAt == nil:
+			return -1
+		case b.CheckedAt == nil:
+			return 1
+		default:
+			return a.CheckedAt.Compare(*b.CheckedAt)
+		}
+	})
+
+	now := time.Now().UTC()
+	claimed := []models.Notification{}
+
+	for _, notification := range pending[:min(limit, len(pending))] {
+		notification.CheckedAt = &now
+		claimed = append(claimed, *notification)
+	}
+
+	return claimed, nil
+}
+
+// Records the given final status for the email operations created before the given time that never finished,
+// so they stop counting as pending. Returns the number of expired operations.
+func (m *Memory) ExpireOperations(ctx context.Context, before time.Time, status string) (int, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	expired := 0
+	for i := range m.notifications {
+		notification := &m.notifications[i]
+		if notification.Channel == "email" && notification.MessageID != nil && notification.OperationStatus == nil && notification.CreatedAt.Before(before) {
+			notification.OperationStatus = &status
+			notification.UpdatedAt = time.Now().UTC()
+			expired++
+		}
+	}
+
+	return expired, nil
+}
+
+// Counts the email notifications created since the given time whose Azure operation has no final status yet,
+// and returns the creation time of the oldest one (nil without pending operations).
+func (m *Memory) OperationBacklog(ctx context.Context, since time.Time) (int, *time.Time, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	count := 0
+	var oldest *time.Time
+
+	for _, notification := range m.notifications {
+		if notification.Channel == "email" && notification.MessageID != nil && notification.OperationStatus == nil && !notification.CreatedAt.Before(since) {
+			count++
+			if createdAt := notification.CreatedAt; oldest == nil || createdAt.Before(*oldest) {
+				oldest = &createdAt
+			}
+		}
+	}
+
+	return count, oldest, nil
+}
+
+// Records the final status of a notification's Azure operation, or returns ErrNotFound.
+// A failure reason marks the notification as failed, unless a delivery report already set its status.
+func (m *Memory) CompleteOperation(ctx context.Context, id int, status string, failure *string) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	for i := range m.notifications {
+		notification := &m.notifications[i]
+		if notification.ID != id {
+			continue
+		}
+
+		notification.OperationStatus = &status
+		if failure != nil && notification.Status == models.NotificationAccepted {
+			notification.Status, notification.Reason = models.NotificationFailed, failure
+		}
+		notification.UpdatedAt = time.Now().UTC()
+
+		return nil
+	}
+
+	return ErrNotFound
+}
+
+// Finds the suppression of a recipient, or returns ErrNotFound.
+func (m *Memory) FindSuppression(ctx context.Context, channel, recipient string) (models.Suppression, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	for _, suppression := range m.suppressions {
+		if suppression.Channel == channel && suppression.Recipient == recipient {
+			return suppression, nil
+		}
+	}
+
+	return models.Suppression{}, ErrNotFound
+}
+
+// Returns the suppressions of the channel (or of every channel if it is empty), newest first.
+func (m *Memory) ListSuppressions(ctx context.Context, channel string) ([]models.Suppression, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	suppressions := []models.Suppression{}
+	for _, suppression := range slices.Backward(m.suppressions) {
+		if channel == "" || suppression.Channel == channel {
+			suppressions = append(suppressions, suppression)
+		}
+	}
+
+	return suppressions, nil
+}
+
+// Suppresses a recipient and sets the suppression's ID and timestamp.
+// Suppressing a recipient again replaces its reason and source, and restarts the timestamp.
+func (m *Memory) AddSuppression(ctx context.Context, suppression *models.Suppression) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.suppressionID++
+	suppression.ID = m.suppressionID
+	suppression.CreatedAt = time.Now().UTC()
+
+	m.suppressions = slices.DeleteFunc(m.suppressions, func(existing models.Suppression) bool {
+		if existing.Channel == suppression.Channel && existing.Recipient == suppression.Recipient {
+			suppression.ID = existing.ID
+			return true
+		}
+		return false
+	})
+	m.suppressions = append(m.suppressions, *suppression)
+
+	return nil
+}
+
+// Removes the suppression of a recipient, or returns ErrNotFound.
+func (m *Memory) RemoveSuppression(ctx context.Context, channel, recipient string) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	count := len(m.suppressions)
+	m.suppressions = slices.DeleteFunc(m.suppressions, func(suppression models.Suppression) bool {
+		return suppression.Channel == channel && suppression.Recipient == recipient
+	})
+
+	if len(m.suppressions) == count {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// Stores a reply and sets its ID and timestamp.
+// Returns ErrDuplicate if a reply with the same message ID was already stored.
+func (m *Memory) CreateReply(ctx context.Context, reply *models.Reply) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	for _, existing := range m.replies {
+		if existing.MessageID == reply.MessageID {
+			return ErrDuplicate
+		}
+	}
+
+	reply.ID = len(m.replies) + 1
+	reply.ReceivedAt = time.Now().UTC()
+	m.replies = append(m.replies, *reply)
+
+	return nil
+}
+
+// Records the channel and message ID a reply was forwarded with, or returns ErrNotFound.
+func (m *Memory) SetReplyForwarded(ctx context.Context, id int, channel, messageID string) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	for i := range m.replies {
+		if m.replies[i].ID == id {
+			m.replies[i].ForwardedChannel, m.replies[i].ForwardedMessageID = &channel, &messageID
+			return nil
+		}
+	}
+
+	return ErrNotFound
+}
+
+// Records an inbound SMS as processed, or returns ErrDuplicate if it already was (e.g. a redelivered event).
+func (m *Memory) RecordInboundMessage(ctx context.Context, messageID string) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if slices.Contains(m.inbound, messageID) {
+		return ErrDuplicate
+	}
+
+	m.inbound = append(m.inbound, messageID)
+
+	return nil
+}
+
+// Returns the key of a client's usage in a billing period.
+func usageKey(clientID string, period time.Time) string {
+	return clientID + "/" + period.Format(time.DateOnly)
+}
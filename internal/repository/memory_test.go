@@ -0,0 +1,193 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"communications/internal/database/models"
+)
+
+// Checks if deleted clients are hidden from lookups and lists.
+func TestMemoryClients(t *testing.T) {
+	ctx := context.Background()
+	memory := NewMemory()
+
+	memory.CreateClient(ctx, &models.Client{ID: "a", Name: "Alpha"})
+	memory.CreateClient(ctx, &models.Client{ID: "b", Name: "Beta"})
+
+	if err := memory.DeleteClient(ctx, "a"); err != nil {
+		t.Fatalf("DeleteClient() error = %v", err)
+	}
+	if err := memory.DeleteClient(ctx, "a"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("DeleteClient() of a deleted client error = %v, want ErrNotFound", err)
+	}
+	if _, err := memory.FindClient(ctx, "a"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("FindClient() of a deleted client error = %v, want ErrNotFound", err)
+	}
+	if client, err := memory.FindClient(ctx, "b"); err != nil || client.Name != "Beta" {
+		t.Errorf("FindClient() = %v, %v, want Beta", client, err)
+	}
+	if clients, _ := memory.ListClients(ctx); len(clients) != 1 || clients[0].ID != "b" {
+		t.Errorf("ListClients() = %v, want only Beta", clients)
+	}
+}
+
+// Checks if exported leads are narrowed down by client and submission time.
+func TestLeadFilter(t *testing.T) {
+	day := time.Date(2025, 6, 15, 12, 0, 0, 0, time.UTC)
+	lead := models.Lead{ClientID: "a", Datetime: day}
+
+	tests := []struct {
+		name   string
+		filter LeadFilter
+		want   bool
+	}{
+		{"No filter", LeadFilter{}, true},
+		{"Same client", LeadFilter{ClientID: "a"}, true},
+		{"Other client", LeadFilter{ClientID: "b"}, false},
+		{"From is inclusive", LeadFilter{From: day}, true},
+		{"Before from", LeadFilter{From: day.Add(time.Second)}, false},
+		{"To is exclusive", LeadFilter{To: day}, false},
+		{"Within range", LeadFilter{From: day.Add(-time.Hour), To: day.Add(time.Hour)}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.filter.matches(lead); got != tt.want {
+				t.Errorf("matches() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// Checks if usage accumulates per client and appears in the report with zero counters for idle clients.
+func TestMemoryUsage(t *testing.T) {
+	ctx := context.Background()
+	memory := NewMemory()
+	quota := 10
+
+	memory.CreateClient(ctx, &models.Client{ID: "b", Name: "Beta"})
+	memory.CreateClient(ctx, &models.Client{ID: "a", Name: "Alpha", EmailQuota: &quota})
+
+	memory.AddUsage(ctx, "a", 1, 1)
+	memory.AddUsage(ctx, "a", 1, 0)
+
+	usage, _ := memory.CurrentUsage(ctx, "a")
+	if usage.Emails != 2 || usage.SMS != 1 {
+		t.Errorf("CurrentUsage() = %d emails, %d SMS, want 2 and 1", usage.Emails, usage.SMS)
+	}
+
+	report, _ := memory.ListUsage(ctx, time.Now())
+	if len(report) != 2 || report[0].ClientName != "Alpha" || report[1].Emails != 0 {
+		t.Fatalf("ListUsage() = %+v, want Alpha with usage and Beta without", report)
+	}
+	if report[0].EmailQuota == nil || *report[0].EmailQuota != 10 {
+		t.Errorf("ListUsage() did not include the client's quota")
+	}
+
+	if previous, _ := memory.ListUsage(ctx, time.Now().AddDate(0, -1, 0)); previous[0].Emails != 0 {
+		t.Errorf("ListUsage() of the previous month = %+v, want zero counters", previous[0])
+	}
+
+	if reserved, _ := memory.ReserveUsage(ctx, "a", 9, 0, &quota, nil); reserved {
+		t.Errorf("ReserveUsage() over the quota was reserved")
+	}
+	if reserved, _ := memory.ReserveUsage(ctx, "a", 8, 1, &quota, nil); !reserved {
+		t.Errorf("ReserveUsage() up to the quota was not reserved")
+	}
+	if usage, _ := memory.CurrentUsage(ctx, "a"); usage.Emails != 10 || usage.SMS != 2 {
+		t.Errorf("CurrentUsage() after reserving = %d emails, %d SMS, want 10 and 2", usage.Emails, usage.SMS)
+	}
+}
+
+// Checks if suppressing a recipient again replaces its reason, and removed suppressions are no longer found.
+func TestMemorySuppressions(t *testing.T) {
+	ctx := context.Background()
+	memory := NewMemory()
+	reason := "Asked to stop"
+
+	email := models.Suppression{Channel: "email", Recipient: "a@example.com", Source: models.SuppressionBounce}
+	memory.AddSuppression(ctx, &email)
+	memory.AddSuppression(ctx, &models.Suppression{Channel: "sms", Recipient: "+12025550199", Source: models.SuppressionOptOut})
+
+	again := models.Suppression{Channel: "email", Recipient: "a@example.com", Reason: &reason, Source: models.SuppressionAdmin}
+	memory.AddSuppression(ctx, &again)
+
+	if again.ID != email.ID {
+		t.Errorf("AddSuppression() again ID = %d, want %d", again.ID, email.ID)
+	}
+	if found, err := memory.FindSuppression(ctx, "email", "a@example.com"); err != nil || found.Source != models.SuppressionAdmin || found.Reason == nil {
+		t.Errorf("FindSuppression() = %+v, %v, want the replaced suppression", found, err)
+	}
+	if list, _ := memory.ListSuppressions(ctx, "email"); len(list) != 1 {
+		t.Errorf("ListSuppressions(email) = %+v, want 1 suppression", list)
+	}
+	if list, _ := memory.ListSuppressions(ctx, ""); len(list) != 2 || list[0].Channel != "email" {
+		t.Errorf("ListSuppressions() = %+v, want 2 suppressions, newest first", list)
+	}
+
+	if err := memory.RemoveSuppression(ctx, "sms", "+12025550199"); err != nil {
+		t.Fatalf("RemoveSuppression() error = %v", err)
+	}
+	if err := memory.RemoveSuppression(ctx, "sms", "+12025550199"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("RemoveSuppression() again error = %v, want ErrNotFound", err)
+	}
+	if _, err := memory.FindSuppression(ctx, "sms", "+12025550199"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("FindSuppression() of a removed suppression error = %v, want ErrNotFound", err)
+	}
+}
+
+// Checks if claimed operations are skipped until their lease passes, so a stuck batch never starves newer ones,
+// and unfinished operations past the age limit are expired.
+func TestMemoryOperations(t *testing.T) {
+	ctx := context.Background()
+	memory := NewMemory()
+	now := time.Now().UTC()
+
+	for i, age := range []time.Duration{25 * time.Hour, 3 * time.Hour, 2 * time.Hour, time.Hour} {
+		messageID := string(rune('a' + i))
+		memory.CreateNotification(ctx, &models.Notification{
+			ClientID:  "a",
+			Channel:   "email",
+			MessageID: &messageID,
+			CreatedAt: now.Add(-age),
+		})
+	}
+	memory.CreateNotification(ctx, &models.Notification{ClientID: "a", Channel: "email", Status: models.NotificationSuppressed})
+
+	since := now.Add(-24 * time.Hour)
+
+	claimIDs := func(checkedBefore time.Time) []int {
+		claimed, err := memory.ClaimOperations(ctx, since, checkedBefore, 2)
+		if err != nil {
+			t.Fatalf("ClaimOperations() error = %v", err)
+		}
+
+		ids := []int{}
+		for _, notification := range claimed {
+			ids = append(ids, notification.ID)
+		}
+
+		return ids
+	}
+
+	if ids := claimIDs(now); !slices.Equal(ids, []int{2, 3}) {
+		t.Errorf("first claim = %v, want [2 3]", ids)
+	}
+	if ids := claimIDs(now.Add(-time.Minute)); !slices.Equal(ids, []int{4}) {
+		t.Errorf("claim within the lease = %v, want [4]", ids)
+	}
+	if ids := claimIDs(time.Now().UTC().Add(time.Minute)); !slices.Equal(ids, []int{2, 3}) {
+		t.Errorf("claim after the lease = %v, want the least recently checked [2 3]", ids)
+	}
+
+	if expired, err := memory.ExpireOperations(ctx, since, "Expired"); err != nil || expired != 1 {
+		t.Fatalf("ExpireOperations() = %d, %v, want 1", expired, err)
+	}
+	if count, _, _ := memory.OperationBacklog(ctx, time.Time{}); count != 3 {
+		t.Errorf("OperationBacklog() after expiring = %d, want 3", count)
+	}
+}
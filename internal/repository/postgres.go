@@ -0,0 +1,622 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"communications/internal/database/models"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Columns of the "clients" table, matching the db tags of models.Client.
+const clientColumns = `"id", "name", "email", "phone", "website", "country", "verified",
+	"email_verified", "phone_verified", "phone_code", "phone_code_expires_at", "phone_code_attempts",
+	"email_quota", "sms_quota", "created_at", "updated_at", "deleted_at"`
+
+// Columns of the "leads" table, matching the db tags of models.Lead.
+const leadColumns = `"id", "datetime", "name", "email", "phone", "client_id", "email_verdict",
+	"utm_source", "utm_medium", "utm_campaign", "utm_term", "utm_content",
+	"referrer", "landing_page", "user_agent", "ip_address", "country", "city"`
+
+// Columns of the "notifications" table, matching the db tags of models.Notification.
+const notificationColumns = `"id", "client_id", "lead_id", "channel", "message_id", "recipient",
+	"status", "status_reason", "operation_status", "checked_at", "created_at", "updated_at"`
+
+// Columns of the "suppressions" table, matching the db tags of models.Suppression.
+const suppressionColumns = `"id", "channel", "recipient", "reason", "source", "created_at"`
+
+// Columns of the "replies" table, matching the db tags of models.Reply.
+const replyColumns = `"id", "client_id", "lead_id", "message_id", "sender", "message",
+	"forwarded_channel", "forwarded_message_id", "received_at"`
+
+// Repositories stored in PostgreSQL.
+type Postgres struct {
+	Pool *pgxpool.Pool
+}
+
+// Ensures Postgres implements every repository.
+var _ Store = (*Postgres)(nil)
+
+// Creates the Postgres repositories using the existing connection pool.
+func NewPostgres(db *pgxpool.Pool) *Postgres {
+	return &Postgres{Pool: db}
+}
+
+// Checks that the database is reachable.
+func (p *Postgres) Ping(ctx context.Context) error {
+	return p.Pool.Ping(ctx)
+}
+
+// Returns the applied migration version and whether it is dirty (half-applied).
+func (p *Postgres) MigrationVersion(ctx context.Context) (version uint, dirty bool, err error) {
+	err = p.Pool.QueryRow(ctx, `select "version", "dirty" from "schema_migrations" limit 1`).Scan(&version, &dirty)
+
+	return version, dirty, err
+}
+
+// Finds a client that has not been deleted by ID, or returns ErrNotFound.
+func (p *Postgres) FindClient(ctx context.Context, id string) (models.Client, error) {
+	rows, err := p.Pool.Query(ctx, `select `+clientColumns+` from "clients" where "id" = $1 and "deleted_at" is null`, id)
+	if err != nil {
+		return models.Client{}, err
+	}
+
+	client, err := pgx.CollectExactlyOneRow(rows, pgx.RowToStructByName[models.Client])
+	if errors.Is(err, pgx.ErrNoRows) {
+		return models.Client{}, ErrNotFound
+	}
+
+	return client, err
+}
+
+// Returns all clients that have not been deleted, oldest first.
+func (p *Postgres) ListClients(ctx context.Context) ([]models.Client, error) {
+	rows, err := p.Pool.Query(ctx, `select `+clientColumns+` from "clients" where "deleted_at" is null order by "created_at"`)
+	if err != nil {
+		return nil, err
+	}
+
+	return pgx.CollectRows(rows, pgx.RowToStructByName[models.Client])
+}
+
+// Stores a new client and sets its timestamps.
+func (p *Postgres) CreateClient(ctx context.Context, client *models.Client) error {
+	return p.Pool.QueryRow(
+		ctx,
+		`insert into "clients" ("id", "name", "email", "phone", "website", "country", "email_quota", "sms_quota")
+		values ($1, $2, $3, $4, $5, $6, $7, $8) returning "created_at", "updated_at"`,
+		client.ID,
+		client.Name,
+		client.Email,
+		client.Phone,
+		client.Website,
+		client.Country,
+		client.EmailQuota,
+		client.SMSQuota,
+	).Scan(&client.CreatedAt, &client.UpdatedAt)
+}
+
+// Soft-deletes a client, keeping its leads, or returns ErrNotFound.
+func (p *Postgres) DeleteClient(ctx context.Context, id string) error {
+	tag, err := p.Pool.Exec(
+		ctx,
+		`update "clients" set "deleted_at" = now(), "updated_at" = now() where "id" = $1 and "deleted_at" is null`,
+		id,
+	)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// Marks the client's email as verified if it still matches, or returns ErrNotFound.
+// The client becomes verified once its phone is verified too.
+func (p *Postgres) VerifyEmail(ctx context.Context, id, email string) error {
+	return p.updateClient(
+		ctx,
+		`update "clients" set "email_verified" = true, "verified" = "phone_verified", "updated_at" = now()
+		where "id" = $1 and "email" = $2 and "deleted_at" is null`,
+		id,
+		email,
+	)
+}
+
+// Stores the hash of a new phone verification code, replacing the pending one and its attempts.
+func (p *Postgres) SetPhoneCode(ctx context.Context, id, code string, expiresAt time.Time) error {
+	return p.updateClient(
+		ctx,
+		`update "clients" set "phone_code" = $2, "phone_code_expires_at" = $3, "phone_code_attempts" = 0, "updated_at" = now()
+		where "id" = $1 and "deleted_at" is null`,
+		id,
+		code,
+		expiresAt,
+	)
+}
+
+// Counts an attempt to enter the pending phone verification code and returns the attempts so far.
+// Counting before the code is compared keeps concurrent guesses within the limit.
+func (p *Postgres) AddPhoneCodeAttempt(ctx context.Context, id string) (int, error) {
+	var attempts int
+
+	err := p.Pool.QueryRow(
+		ctx,
+		`update "clients" set "phone_code_attempts" = "phone_code_attempts" + 1
+		where "id" = $1 and "deleted_at" is null returning "phone_code_attempts"`,
+		id,
+	).Scan(&attempts)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, ErrNotFound
+	}
+
+	return attempts, err
+}
+
+// Marks the client's phone as verified if it still matches and clears the pending code, or returns ErrNotFound.
+// The client becomes verified once its email is verified too.
+func (p *Postgres) VerifyPhone(ctx context.Context, id, phone string) error {
+	return p.updateClient(
+		ctx,
+		`update "clients" set "phone_verified" = true, "verified" = "email_verified",
+		"phone_code" = null, "phone_code_expires_at" = null, "phone_code_attempts" = 0, "updated_at" = now()
+		where "id" = $1 and "phone" = $2 and "deleted_at" is null`,
+		id,
+		phone,
+	)
+}
+
+// Runs an update of a single client, returning ErrNotFound if no row matched.
+func (p *Postgres) updateClient(ctx context.Context, sql string, args ...any) error {
+	tag, err := p.Pool.Exec(ctx, sql, args...)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// Finds a lead by ID, or returns ErrNotFound.
+func (p *Postgres) FindLead(ctx context.Context, id int) (models.Lead, error) {
+	rows, err := p.Pool.Query(ctx, `select `+leadColumns+` from "leads" where "id" = $1`, id)
+	if err != nil {
+		return models.Lead{}, err
+	}
+
+	lead, err := pgx.CollectExactlyOneRow(rows, pgx.RowToStructByName[models.Lead])
+	if errors.Is(err, pgx.ErrNoRows) {
+		return models.Lead{}, ErrNotFound
+	}
+
+	return lead, err
+}
+
+// Stores a new lead and sets its ID and submission time.
+func (p *Postgres) CreateLead(ctx context.Context, lead *models.Lead) error {
+	return p.Pool.QueryRow(
+		ctx,
+		`insert into "leads" (
+			"name", "email", "phone", "client_id", "email_verdict",
+			"utm_source", "utm_medium", "utm_campaign", "utm_term", "utm_content",
+			"referrer", "landing_page", "user_agent", "ip_address", "country", "city"
+		) values ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16)
+		returning "id", "datetime"`,
+		lead.Name,
+		lead.Email,
+		lead.Phone,
+		lead.ClientID,
+		lead.EmailVerdict,
+		lead.UTMSource,
+		lead.UTMMedium,
+		lead.UTMCampaign,
+		lead.UTMTerm,
+		lead.UTMContent,
+		lead.Referrer,
+		lead.LandingPage,
+		lead.UserAgent,
+		lead.IPAddress,
+		lead.Country,
+		lead.City,
+	).Scan(&lead.ID, &lead.Datetime)
+}
+
+// Passes the leads matching the filter to fn, oldest first, and stops at the first error.
+// Streams the rows without loading them all into memory.
+func (p *Postgres) ExportLeads(ctx context.Context, filter LeadFilter, fn func(models.Lead) error) error {
+	var clientID *string
+	var from, to *time.Time
+
+	if filter.ClientID != "" {
+		clientID = &filter.ClientID
+	}
+	if !filter.From.IsZero() {
+		from = &filter.From
+	}
+	if !filter.To.IsZero() {
+		to = &filter.To
+	}
+
+	rows, err := p.Pool.Query(
+		ctx,
+		`select `+leadColumns+` from "leads"
+		where ($1::uuid is null or "client_id" = $1)
+			and ($2::timestamp is null or "datetime" >= $2)
+			and ($3::timestamp is null or "datetime" < $3)
+		order by "datetime", "id"`,
+		clientID,
+		from,
+		to,
+	)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		lead, err := pgx.RowToStructByName[models.Lead](rows)
+		if err != nil {
+			return err
+		}
+		if err := fn(lead); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
+
+// Returns the client's counters for the current billing period.
+func (p *Postgres) CurrentUsage(ctx context.Context, clientID string) (models.Usage, error) {
+	usage := models.Usage{ClientID: clientID}
+
+	err := p.Pool.QueryRow(
+		ctx,
+		`select date_trunc('month', now())::date, coalesce(sum("emails"), 0), coalesce(sum("sms"), 0)
+		from "client_usage" where "client_id" = $1 and "period" = date_trunc('month', now())::date`,
+		clientID,
+	).Scan(&usage.Period, &usage.Emails, &usage.SMS)
+
+	return usage, err
+}
+
+// Adds sent notifications to the client's counters for the current billing period.
+func (p *Postgres) AddUsage(ctx context.Context, clientID string, emails, sms int) error {
+	_, err := p.Pool.Exec(
+		ctx,
+		`insert into "client_usage" ("client_id", "period", "emails", "sms") values ($1, date_trunc('month', now())::date, $2, $3)
+		on conflict ("client_id", "period") do update set
+			"emails" = "client_usage"."emails" + excluded."emails",
+			"sms" = "client_usage"."sms" + excluded."sms",
+			"updated_at" = now()`,
+		clientID,
+		emails,
+		sms,
+	)
+
+	return err
+}
+
+// Adds notifications to the client's counters for the current billing period only if the counters stay within
+// the quotas (nil means unlimited), in a single statement, so concurrent reservations never exceed a quota.
+// Returns false, without changing the counters, if a quota would be exceeded.
+func (p *Postgres) ReserveUsage(ctx context.Context, clientID string, emails, sms int, emailQuota, smsQuota *int) (bool, error) {
+	err := p.Pool.QueryRow(
+		ctx,
+		`insert into "client_usage" ("client_id", "period", "emails", "sms")
+		select $1, date_trunc('month', now())::date, $2, $3
+		where ($2 = 0 or $4::int is null or $2 <= $4) and ($3 = 0 or $5::int is null or $3 <= $5)
+		on conflict ("client_id", "period") do update set
+			"emails" = "client_usage"."emails" + excluded."emails",
+			"sms" = "client_usage"."sms" + excluded."sms",
+			"updated_at" = now()
+		where ($2 = 0 or $4::int is null or "client_usage"."emails" + excluded."emails" <= $4)
+			and ($3 = 0 or $5::int is null or "client_usage"."sms" + excluded."sms" <= $5)
+		returning true`,
+		clientID,
+		emails,
+		sms,
+		emailQuota,
+		smsQuota,
+	).Scan(new(bool))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return false, nil
+	}
+
+	return err == nil, err
+}
+
+// Returns the usage of every client during the billing period that contains the month.
+// Includes every client that has not been deleted, with zero counters if it sent nothing in the period.
+// Quotas are the clients' own quotas, without the configured defaults.
+func (p *Postgres) ListUsage(ctx context.Context, month time.Time) ([]models.Usage, error) {
+	rows, err := p.Pool.Query(
+		ctx,
+		`select c."id", c."name", date_trunc('month', $1::date)::date,
+			coalesce(u."emails", 0), coalesce(u."sms", 0), c."email_quota", c."sms_quota"
+		from "clients" c
+		left join "client_usage" u on u."client_id" = c."id" and u."period" = date_trunc('month', $1::date)::date
+		where c."deleted_at" is null
+		order by c."name"`,
+		month,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return pgx.CollectRows(rows, func(row pgx.CollectableRow) (models.Usage, error) {
+		var usage models.Usage
+		err := row.Scan(&usage.ClientID, &usage.ClientName, &usage.Period, &usage.Emails, &usage.SMS, &usage.EmailQuota, &usage.SMSQuota)
+		return usage, err
+	})
+}
+
+// Stores a notification and sets its ID and timestamps. The status defaults to accepted (sent).
+func (p *Postgres) CreateNotification(ctx context.Context, notification *models.Notification) error {
+	if notification.Status == "" {
+		notification.Status = models.NotificationAccepted
+	}
+
+	return p.Pool.QueryRow(
+		ctx,
+		`insert into "notifications" ("client_id", "lead_id", "channel", "message_id", "recipient", "status")
+		values ($1, $2, $3, $4, $5, $6) returning "id", "created_at", "updated_at"`,
+		notification.ClientID,
+		notification.LeadID,
+		notification.Channel,
+		notification.MessageID,
+		notification.Recipient,
+		notification.Status,
+	).Scan(&notification.ID, &notification.CreatedAt, &notification.UpdatedAt)
+}
+
+// Links stored notifications to the lead they were sent for.
+func (p *Postgres) LinkNotifications(ctx context.Context, leadID int, ids []int) error {
+	_, err := p.Pool.Exec(ctx, `update "notifications" set "lead_id" = $1, "updated_at" = now() where "id" = any($2)`, leadID, ids)
+	return err
+}
+
+// Sets the delivery status of the notification with the provider's message ID, or returns ErrNotFound.
+func (p *Postgres) UpdateNotificationStatus(ctx context.Context, channel, messageID, status string, reason *string) error {
+	tag, err := p.Pool.Exec(
+		ctx,
+		`update "notifications" set "status" = $3, "status_reason" = $4, "updated_at" = now()
+		where "channel" = $1 and "message_id" = $2`,
+		channel,
+		messageID,
+		status,
+		reason,
+	)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// Returns up to limit notifications sent to the recipient since the given time that are linked to a lead
+// (ignoring suppressed ones, which were never sent), latest first.
+func (p *Postgres) RecentNotifications(ctx context.Context, channel, recipient string, since time.Time, limit int) ([]models.Notification, error) {
+	rows, err := p.Pool.Query(
+		ctx,
+		`select `+notificationColumns+` from "notifications"
+		where "channel" = $1 and "recipient" = $2 and "message_id" is not null and "lead_id" is not null and "created_at" >= $3
+		order by "created_at" desc, "id" desc limit $4`,
+		channel,
+		recipient,
+		since,
+		limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return pgx.CollectRows(rows, pgx.RowToStructByName[models.Notification])
+}
+
+// Claims up to limit email notifications created since the given time whose Azure operation has not finished
+// and was not checked since checkedBefore, least recently checked first, and records them as checked now.
+// Rows claimed by another replica are skipped, so replicas never poll the same operations at once.
+func (p *Postgres) ClaimOperations(ctx context.Context, since, checkedBefore time.Time, limit int) ([]models.Notification, error) {
+	rows, err := p.Pool.Query(
+		ctx,
+		`update "notifications" set "checked_at" = now()
+		where "id" in (
+			select "id" from "notifications"
+			where "channel" = 'email' and "message_id" is not null and "operation_status" is null and "created_at" >= $1
+			and ("checked_at" is null or "checked_at" < $2)
+			order by "checked_at" nulls first, "created_at" limit $3
+			for update skip locked
+		)
+		returning `+notificationColumns,
+		since,
+		checkedBefore,
+		limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return pgx.CollectRows(rows, pgx.RowToStructByName[models.Notification])
+}
+
+// Counts the email notifications created since the given time whose Azure operation has no final status yet,
+// and returns the creation time of the oldest one (nil without pending operations).
+func (p *Postgres) OperationBacklog(ctx context.Context, since time.Time) (count int, oldest *time.Time, err error) {
+	err = p.Pool.QueryRow(
+		ctx,
+		`select count(*), min("created_at") from "notifications"
+		where "channel" = 'email' and "message_id" is not null and "operation_status" is null and "created_at" >= $1`,
+		since,
+	).Scan(&count, &oldest)
+
+	return count, oldest, err
+}
+
+// Records the given final status for the email operations created before the given time that never finished,
+// so they stop counting as pending. Returns the number of expired operations.
+func (p *Postgres) ExpireOperations(ctx context.Context, before time.Time, status string) (int, error) {
+	tag, err := p.Pool.Exec(
+		ctx,
+		`update "notifications" set "operation_status" = $2, "updated_at" = now()
+		where "channel" = 'email' and "message_id" is not null and "operation_status" is null and "created_at" < $1`,
+		before,
+		status,
+	)
+
+	return int(tag.RowsAffected()), err
+}
+
+// Records the final status of a notification's Azure operation, or returns ErrNotFound.
+// A failure reason marks the notification as failed, unless a delivery report already set its status.
+func (p *Postgres) CompleteOperation(ctx context.Context, id int, status string, failure *string) error {
+	tag, err := p.Pool.Exec(
+		ctx,
+		`update "notifications" set "operation_status" = $2,
+		"status" = case when $3::text is not null and "status" = 'accepted' then 'failed' else "status" end,
+		"status_reason" = case when $3::text is not null and "status" = 'accepted' then $3 else "status_reason" end,
+		"updated_at" = now()
+		where "id" = $1`,
+		id,
+		status,
+		failure,
+	)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// Finds the suppression of a recipient, or returns ErrNotFound.
+func (p *Postgres) FindSuppression(ctx context.Context, channel, recipient string) (models.Suppression, error) {
+	rows, err := p.Pool.Query(
+		ctx,
+		`select `+suppressionColumns+` from "suppressions" where "channel" = $1 and "recipient" = $2`,
+		channel,
+		recipient,
+	)
+	if err != nil {
+		return models.Suppression{}, err
+	}
+
+	suppression, err := pgx.CollectExactlyOneRow(rows, pgx.RowToStructByName[models.Suppression])
+	if errors.Is(err, pgx.ErrNoRows) {
+		return suppression, ErrNotFound
+	}
+
+	return suppression, err
+}
+
+// Returns the suppressions of the channel (or of every channel if it is empty), newest first.
+func (p *Postgres) ListSuppressions(ctx context.Context, channel string) ([]models.Suppression, error) {
+	rows, err := p.Pool.Query(
+		ctx,
+		`select `+suppressionColumns+` from "suppressions"
+		where $1 = '' or "channel" = $1 order by "created_at" desc, "id" desc`,
+		channel,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return pgx.CollectRows(rows, pgx.RowToStructByName[models.Suppression])
+}
+
+// Suppresses a recipient and sets the suppression's ID and timestamp.
+// Suppressing a recipient again replaces its reason and source, and restarts the timestamp.
+func (p *Postgres) AddSuppression(ctx context.Context, suppression *models.Suppression) error {
+	return p.Pool.QueryRow(
+		ctx,
+		`insert into "suppressions" ("channel", "recipient", "reason", "source") values ($1, $2, $3, $4)
+		on conflict ("channel", "recipient") do update
+		set "reason" = excluded."reason", "source" = excluded."source", "created_at" = now()
+		returning "id", "created_at"`,
+		suppression.Channel,
+		suppression.Recipient,
+		suppression.Reason,
+		suppression.Source,
+	).Scan(&suppression.ID, &suppression.CreatedAt)
+}
+
+// Removes the suppression of a recipient, or returns ErrNotFound.
+func (p *Postgres) RemoveSuppression(ctx context.Context, channel, recipient string) error {
+	tag, err := p.Pool.Exec(ctx, `delete from "suppressions" where "channel" = $1 and "recipient" = $2`, channel, recipient)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// Stores a reply and sets its ID and timestamp.
+// Returns ErrDuplicate if a reply with the same message ID was already stored.
+func (p *Postgres) CreateReply(ctx context.Context, reply *models.Reply) error {
+	err := p.Pool.QueryRow(
+		ctx,
+		`insert into "replies" ("client_id", "lead_id", "message_id", "sender", "message") values ($1, $2, $3, $4, $5)
+		on conflict ("message_id") do nothing returning "id", "received_at"`,
+		reply.ClientID,
+		reply.LeadID,
+		reply.MessageID,
+		reply.Sender,
+		reply.Message,
+	).Scan(&reply.ID, &reply.ReceivedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return ErrDuplicate
+	}
+
+	return err
+}
+
+// Records the channel and message ID a reply was forwarded with, or returns ErrNotFound.
+func (p *Postgres) SetReplyForwarded(ctx context.Context, id int, channel, messageID string) error {
+	tag, err := p.Pool.Exec(
+		ctx,
+		`update "replies" set "forwarded_channel" = $2, "forwarded_message_id" = $3 where "id" = $1`,
+		id,
+		channel,
+		messageID,
+	)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// Records an inbound SMS as processed, or returns ErrDuplicate if it already was (e.g. a redelivered event).
+func (p *Postgres) RecordInboundMessage(ctx context.Context, messageID string) error {
+	tag, err := p.Pool.Exec(
+		ctx,
+		`insert into "inbound_messages" ("message_id") values ($1) on conflict ("message_id") do nothing`,
+		messageID,
+	)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrDuplicate
+	}
+
+	return nil
+}
@@ -0,0 +1,108 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"communications/internal/database/models"
+)
+
+// Returned when a record does not exist (or, for clients, has been deleted).
+var ErrNotFound = errors.New("not found")
+
+// Returned when a record with the same unique key was already stored (e.g. a redelivered event).
+var ErrDuplicate = errors.New("already exists")
+
+// Stores the clients that receive leads and the verification state of their contacts.
+// Deleted clients are never returned, and verification methods return ErrNotFound for them.
+type ClientRepository interface {
+	FindClient(ctx context.Context, id string) (models.Client, error)
+	ListClients(ctx context.Context) ([]models.Client, error)
+	CreateClient(ctx context.Context, client *models.Client) error
+	DeleteClient(ctx context.Context, id string) error
+	VerifyEmail(ctx context.Context, id, email string) error
+	SetPhoneCode(ctx context.Context, id, code string, expiresAt time.Time) error
+	AddPhoneCodeAttempt(ctx context.Context, id string) (int, error)
+	VerifyPhone(ctx context.Context, id, phone string) error
+}
+
+// Stores the submitted leads.
+type LeadRepository interface {
+	FindLead(ctx context.Context, id int) (models.Lead, error)
+	CreateLead(ctx context.Context, lead *models.Lead) error
+	ExportLeads(ctx context.Context, filter LeadFilter, fn func(models.Lead) error) error
+}
+
+// Stores the number of notifications sent per client and billing period (calendar month).
+// Negative counts passed to AddUsage release notifications reserved with ReserveUsage but never sent.
+type UsageRepository interface {
+	CurrentUsage(ctx context.Context, clientID string) (models.Usage, error)
+	AddUsage(ctx context.Context, clientID string, emails, sms int) error
+	ReserveUsage(ctx context.Context, clientID string, emails, sms int, emailQuota, smsQuota *int) (bool, error)
+	ListUsage(ctx context.Context, month time.Time) ([]models.Usage, error)
+}
+
+// Stores the notifications sent to clients, their delivery status and the outcome of Azure email operations.
+type NotificationRepository interface {
+	CreateNotification(ctx context.Context, notification *models.Notification) error
+	LinkNotifications(ctx context.Context, leadID int, ids []int) error
+	UpdateNotificationStatus(ctx context.Context, channel, messageID, status string, reason *string) error
+	RecentNotifications(ctx context.Context, channel, recipient string, since time.Time, limit int) ([]models.Notification, error)
+	ClaimOperations(ctx context.Context, since, checkedBefore time.Time, limit int) ([]models.Notification, error)
+	ExpireOperations(ctx context.Context, before time.Time, status string) (int, error)
+	OperationBacklog(ctx context.Context, since time.Time) (count int, oldest *time.Time, err error)
+	CompleteOperation(ctx context.Context, id int, status string, failure *string) error
+}
+
+// Stores the email addresses and phone numbers that must no longer receive notifications.
+// Recipients are matched exactly, so callers normalize them first (see models.Suppression).
+type SuppressionRepository interface {
+	FindSuppression(ctx context.Context, channel, recipient string) (models.Suppression, error)
+	ListSuppressions(ctx context.Context, channel string) ([]models.Suppression, error)
+	AddSuppression(ctx context.Context, suppression *models.Suppression) error
+	RemoveSuppression(ctx context.Context, channel, recipient string) error
+}
+
+// Stores the SMS replies of clients, linked to the leads they are about.
+type ReplyRepository interface {
+	CreateReply(ctx context.Context, reply *models.Reply) error
+	SetReplyForwarded(ctx context.Context, id int, channel, messageID string) error
+	RecordInboundMessage(ctx context.Context, messageID string) error
+}
+
+// Reports the health of the storage itself, for the health and readiness checks.
+type HealthRepository interface {
+	Ping(ctx context.Context) error
+	MigrationVersion(ctx context.Context) (version uint, dirty bool, err error)
+}
+
+// Implements every repository, e.g. *Postgres in production and *Memory in tests.
+type Store interface {
+	HealthRepository
+	ClientRepository
+	LeadRepository
+	UsageRepository
+	NotificationRepository
+	SuppressionRepository
+	ReplyRepository
+}
+
+// Narrows down the leads returned by ExportLeads; zero values disable a filter.
+type LeadFilter struct {
+	ClientID string    // Only leads of this client.
+	From     time.Time // Only leads submitted at or after this time.
+	To       time.Time // Only leads submitted before this time.
+}
+
+// Reports whether the lead passes the filter.
+func (f LeadFilter) matches(lead models.Lead) bool {
+	return (f.ClientID == "" || lead.ClientID == f.ClientID) &&
+		(f.From.IsZero() || !lead.Datetime.Before(f.From)) &&
+		(f.To.IsZero() || lead.Datetime.Before(f.To))
+}
+
+// Returns the first day of the billing period that contains t.
+func period(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,90 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// Coordinates background work and the ordered release of application resources on shutdown.
+// Components register their workers and closers at startup; Shutdown stops them in a safe order.
+type Lifecycle struct {
+	ctx     context.Context
+	cancel  context.CancelFunc
+	work    sync.WaitGroup
+	mutex   sync.Mutex
+	closers []closer
+}
+
+// A named function releasing a resource (e.g. the database pool) on shutdown.
+type closer struct {
+	name string
+	fn   func(ctx context.Context) error
+}
+
+// Creates a lifecycle whose context stays active until Shutdown is called.
+func NewLifecycle() *Lifecycle {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	return &Lifecycle{ctx: ctx, cancel: cancel}
+}
+
+// Runs a long-lived background worker (e.g. a cleanup loop).
+// The worker must return once the given context is canceled, which happens when Shutdown begins.
+func (l *Lifecycle) Go(worker func(ctx context.Context)) {
+	l.work.Add(1)
+
+	go func() {
+		defer l.work.Done()
+		worker(l.ctx)
+	}()
+}
+
+// Marks the start of a unit of in-flight work (e.g. sending a notification) that Shutdown must wait for.
+// Returns the function to call once the work is finished.
+func (l *Lifecycle) Track() func() {
+	l.work.Add(1)
+
+	return l.work.Done
+}
+
+// Registers a function releasing a resource on shutdown.
+// Closers run after all work has drained, in reverse order of registration.
+func (l *Lifecycle) OnStop(name string, fn func(ctx context.Context) error) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	l.closers = append(l.closers, closer{name: name, fn: fn})
+}
+
+// Stops background workers, waits for tracked work to finish and then runs the closers.
+// Gives up waiting once ctx is done, but still runs the closers. Returns all errors joined together.
+func (l *Lifecycle) Shutdown(ctx context.Context) error {
+	l.cancel()
+
+	var errs []error
+
+	drained := make(chan struct{})
+	go func() {
+		l.work.Wait()
+		close(drained)
+	}()
+
+	select {
+	case <-drained:
+	case <-ctx.Done():
+		errs = append(errs, fmt.Errorf("background work did not finish: %w", ctx.Err()))
+	}
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	for i := len(l.closers) - 1; i >= 0; i-- {
+		if err := l.closers[i].fn(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("unable to close %s: %w", l.closers[i].name, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
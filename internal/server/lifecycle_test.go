@@ -0,0 +1,84 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+)
+
+// Checks if Shutdown stops workers and waits for tracked work before running closers in reverse order.
+func TestLifecycleShutdown(t *testing.T) {
+	lifecycle := NewLifecycle()
+	var events []string
+	record := make(chan string, 10)
+
+	lifecycle.Go(func(ctx context.Context) {
+		<-ctx.Done()
+		record <- "worker stopped"
+	})
+
+	done := lifecycle.Track()
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		record <- "notification sent"
+		done()
+	}()
+
+	lifecycle.OnStop("database", func(ctx context.Context) error {
+		record <- "database closed"
+		return nil
+	})
+	lifecycle.OnStop("geoip", func(ctx context.Context) error {
+		record <- "geoip closed"
+		return nil
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	if err := lifecycle.Shutdown(ctx); err != nil {
+		t.Fatalf("Shutdown() error = %v", err)
+	}
+
+	close(record)
+	for event := range record {
+		events = append(events, event)
+	}
+
+	if len(events) != 4 {
+		t.Fatalf("events = %v, want 4 events", events)
+	}
+	if !slices.Contains(events[:2], "worker stopped") || !slices.Contains(events[:2], "notification sent") {
+		t.Errorf("work was not drained before the closers ran: %v", events)
+	}
+	if !slices.Equal(events[2:], []string{"geoip closed", "database closed"}) {
+		t.Errorf("closers ran in order %v, want geoip then database", events[2:])
+	}
+}
+
+// Checks if Shutdown still runs closers and reports errors when work does not finish in time.
+func TestLifecycleShutdownTimeout(t *testing.T) {
+	lifecycle := NewLifecycle()
+	closed := false
+
+	done := lifecycle.Track()
+	defer done()
+
+	lifecycle.OnStop("database", func(ctx context.Context) error {
+		closed = true
+		return errors.New("close failed")
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	err := lifecycle.Shutdown(ctx)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Shutdown() error = %v, want the drain timeout", err)
+	}
+	if !closed {
+		t.Errorf("closer did not run after the drain timeout")
+	}
+}
@@ -2,42 +2,58 @@ package server
 
 import (
 	"context"
-	"log"
+	"errors"
+	"fmt"
+	"log/slog"
 	"net/http"
-	"os"
-	"os/signal"
-	"syscall"
 	"time"
 )
 
-// Starts the HTTP server on the specified port and handles graceful shutdown on SIGINT or SIGTERM.
-// Used to ensure the server can be stopped cleanly without dropping in-flight requests.
-func Listen(port string, handler http.Handler) {
+// Starts the HTTP server on the specified port and runs it until ctx is canceled (e.g. on SIGINT or SIGTERM)
+// or the server fails. Then shuts the application down in order within the grace period: stop accepting
+// HTTP and finish in-flight requests, drain background work (e.g. notifications), and finally close
+// resources such as the database pool. Returns every error encountered, or nil on a clean shutdown.
+func Listen(ctx context.Context, port string, handler http.Handler, lifecycle *Lifecycle, grace time.Duration) error {
 	srv := &http.Server{
 		Addr:    ":" + port,
 		Handler: handler,
 	}
 
+	listenErr := make(chan error, 1)
+
 	go func() {
-		log.Printf("Starting server on %s", srv.Addr)
+		slog.Info("Starting server", "address", srv.Addr)
 
-		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-			log.Fatalf("listen: %s\n", err)
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			listenErr <- err
 		}
+		close(listenErr)
 	}()
 
-	quit := make(chan os.Signal, 1)
-	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
-	<-quit
+	var errs []error
+
+	select {
+	case <-ctx.Done():
+	case err := <-listenErr:
+		errs = append(errs, fmt.Errorf("unable to start the server: %w", err))
+	}
 
-	log.Println("Shutting down server...")
+	slog.Info("Shutting down server", "grace_period", grace.String())
 
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), grace)
 	defer cancel()
 
-	if err := srv.Shutdown(ctx); err != nil {
-		log.Fatalf("Server forced to shutdown: %s", err)
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		errs = append(errs, fmt.Errorf("server forced to shutdown: %w", err))
+	}
+
+	if err := lifecycle.Shutdown(shutdownCtx); err != nil {
+		errs = append(errs, err)
+	}
+
+	if len(errs) == 0 {
+		slog.Info("Server exited gracefully")
 	}
 
-	log.Println("Server exited gracefully")
+	return errors.Join(errs...)
 }
@@ -0,0 +1,25 @@
+package services
+
+import (
+	"net"
+	"net/http"
+	"time"
+)
+
+// Shared client used by services that were not given a configured one (e.g. in tools and tests).
+var defaultHTTPClient = NewHTTPClient(10 * time.Second)
+
+// Creates the HTTP client shared by all outbound provider calls.
+// Reuses connections across requests and bounds every phase of a call, so a hung provider endpoint
+// can never block a request goroutine for longer than the timeout.
+func NewHTTPClient(timeout time.Duration) *http.Client {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.DialContext = (&net.Dialer{Timeout: min(timeout, 5*time.Second), KeepAlive: 30 * time.Second}).DialContext
+	transport.TLSHandshakeTimeout = min(timeout, 5*time.Second)
+	transport.ResponseHeaderTimeout = timeout
+	transport.MaxIdleConns = 100
+	transport.MaxIdleConnsPerHost = 20
+	transport.IdleConnTimeout = 90 * time.Second
+
+	return &http.Client{Transport: transport, Timeout: timeout}
+}
@@ -0,0 +1,60 @@
+package services
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"communications/internal/config"
+	"communications/internal/database/dto"
+	"communications/internal/repository"
+)
+
+// Checks if a hung provider endpoint is abandoned after the client timeout or the context deadline.
+func TestSendEmailTimeout(t *testing.T) {
+	release := make(chan struct{})
+	azure := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer azure.Close()
+	defer close(release)
+
+	to := "client@example.com"
+	body := &dto.CreateLeadDTO{Name: "John Doe", Email: "john@example.com", Phone: "+12345678901"}
+
+	tests := []struct {
+		name    string
+		timeout time.Duration
+		ctx     func() (context.Context, context.CancelFunc)
+	}{
+		{"Client timeout", 100 * time.Millisecond, func() (context.Context, context.CancelFunc) {
+			return context.WithCancel(context.Background())
+		}},
+		{"Context deadline", time.Minute, func() (context.Context, context.CancelFunc) {
+			return context.WithTimeout(context.Background(), 100*time.Millisecond)
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := NewService(repository.NewMemory(), &config.Config{AzureURL: "endpoint=" + azure.URL + ";accesskey=secret"})
+			service.HTTPClient = NewHTTPClient(tt.timeout)
+
+			ctx, cancel := tt.ctx()
+			defer cancel()
+
+			start := time.Now()
+			if _, err := service.SendEmail(ctx, &to, body); err == nil {
+				t.Fatalf("SendEmail() error = nil, want a timeout")
+			}
+			if elapsed := time.Since(start); elapsed > 2*time.Second {
+				t.Errorf("SendEmail() returned after %v, want it to give up quickly", elapsed)
+			}
+		})
+	}
+}
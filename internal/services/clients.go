@@ -0,0 +1,57 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"communications/internal/database/models"
+	"communications/internal/utils"
+
+	"github.com/google/uuid"
+)
+
+// Result of checking a client's contact details.
+// Used by the client check command to spot clients whose notifications cannot be delivered.
+type ClientCheck struct {
+	Email EmailVerdict // Deliverability verdict of the client's email address.
+	Phone bool         // Indicates if the phone number is valid E.164 for its calling code.
+}
+
+// Validates and normalizes a new client's details, then stores it with a generated ID.
+// Used by the client add command instead of inserting clients with raw SQL.
+func (s *Service) CreateClient(ctx context.Context, client *models.Client) error {
+	if !utils.ValidateAndNormalizeName(&client.Name) {
+		return fmt.Errorf("name must be %d-%d characters long", utils.MinNameLength, utils.MaxNameLength)
+	}
+
+	client.Email = strings.ToLower(strings.TrimSpace(client.Email))
+	if len(client.Email) > utils.MaxEmailLength || !utils.ValidateEmail(client.Email) {
+		return errors.New("email must be a valid email address")
+	}
+
+	country := ""
+	if client.Country != nil {
+		country = strings.ToUpper(*client.Country)
+		client.Country = &country
+	}
+
+	if !utils.NormalizePhoneNumber(&client.Phone, country) {
+		return errors.New("phone must be a valid phone number in international format or national format of the country")
+	}
+
+	client.ID = uuid.NewString()
+
+	return s.Clients.CreateClient(ctx, client)
+}
+
+// Checks if the client's email can receive mail and its phone number is valid for its calling code.
+func (s *Service) CheckClient(ctx context.Context, client *models.Client) ClientCheck {
+	phone := client.Phone
+
+	return ClientCheck{
+		Email: s.CheckEmailDeliverability(ctx, client.Email),
+		Phone: utils.NormalizePhoneNumber(&phone, ""),
+	}
+}
@@ -0,0 +1,85 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strings"
+	"time"
+)
+
+// Result of the submitter email deliverability check, stored with each lead.
+type EmailVerdict string
+
+const (
+	EmailVerdictDeliverable   EmailVerdict = "deliverable"   // The domain publishes MX or A/AAAA records.
+	EmailVerdictUndeliverable EmailVerdict = "undeliverable" // The domain does not exist or refuses mail.
+	EmailVerdictDisposable    EmailVerdict = "disposable"    // The domain belongs to a disposable email provider.
+	EmailVerdictUnknown       EmailVerdict = "unknown"       // The DNS lookup failed, so deliverability is unknown.
+)
+
+// Deliverability check modes, configured through EMAIL_CHECK.
+const (
+	EmailCheckOff    = "off"    // Skips the check entirely.
+	EmailCheckFlag   = "flag"   // Records the verdict on the lead but accepts every address.
+	EmailCheckReject = "reject" // Rejects disposable and undeliverable addresses.
+)
+
+// Checks whether the email's domain is disposable or lacks the DNS records needed to receive mail.
+// Follows RFC 5321 by falling back to A/AAAA records when no MX records exist, and RFC 7505 for null MX.
+// Transient DNS failures yield EmailVerdictUnknown so that a flaky resolver never blocks a lead.
+func (s *Service) CheckEmailDeliverability(ctx context.Context, email string) EmailVerdict {
+	domain := strings.ToLower(email[strings.LastIndex(email, "@")+1:])
+
+	if s.isDisposableDomain(domain) {
+		return EmailVerdictDisposable
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	records, err := s.Resolver.LookupMX(ctx, domain)
+	if err == nil && len(records) > 0 {
+		if len(records) == 1 && records[0].Host == "." {
+			return EmailVerdictUndeliverable
+		}
+
+		return EmailVerdictDeliverable
+	}
+	if err != nil && !isNotFound(err) {
+		return EmailVerdictUnknown
+	}
+
+	hosts, err := s.Resolver.LookupHost(ctx, domain)
+	if err == nil && len(hosts) > 0 {
+		return EmailVerdictDeliverable
+	}
+	if err != nil && !isNotFound(err) {
+		return EmailVerdictUnknown
+	}
+
+	return EmailVerdictUndeliverable
+}
+
+// Reports whether the domain, or any of its parent domains, is on the disposable list.
+func (s *Service) isDisposableDomain(domain string) bool {
+	for _, disposable := range s.Cfg.DisposableEmails {
+		disposable = strings.ToLower(strings.TrimSpace(disposable))
+		if disposable == "" {
+			continue
+		}
+
+		if domain == disposable || strings.HasSuffix(domain, "."+disposable) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Reports whether the DNS error means the record does not exist, as opposed to a lookup failure.
+func isNotFound(err error) bool {
+	var dnsError *net.DNSError
+
+	return errors.As(err, &dnsError) && dnsError.IsNotFound
+}
@@ -0,0 +1,83 @@
+package services
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"communications/internal/config"
+)
+
+// Fake DNS resolver that answers from in-memory records instead of the network.
+type fakeResolver struct {
+	mx    map[string][]*net.MX
+	hosts map[string][]string
+	err   error
+}
+
+func (r *fakeResolver) LookupMX(ctx context.Context, name string) ([]*net.MX, error) {
+	if r.err != nil {
+		return nil, r.err
+	}
+	if records, ok := r.mx[name]; ok {
+		return records, nil
+	}
+
+	return nil, &net.DNSError{Err: "no such host", Name: name, IsNotFound: true}
+}
+
+func (r *fakeResolver) LookupHost(ctx context.Context, host string) ([]string, error) {
+	if r.err != nil {
+		return nil, r.err
+	}
+	if hosts, ok := r.hosts[host]; ok {
+		return hosts, nil
+	}
+
+	return nil, &net.DNSError{Err: "no such host", Name: host, IsNotFound: true}
+}
+
+// Checks if the deliverability verdict reflects the disposable list and the domain's DNS records.
+func TestCheckEmailDeliverability(t *testing.T) {
+	resolver := &fakeResolver{
+		mx: map[string][]*net.MX{
+			"example.com":  {{Host: "mx.example.com.", Pref: 10}},
+			"nullmx.com":   {{Host: ".", Pref: 0}},
+			"yopmail.com":  {{Host: "mx.yopmail.com.", Pref: 10}},
+			"company.test": {{Host: "mx1.company.test.", Pref: 10}, {Host: "mx2.company.test.", Pref: 20}},
+		},
+		hosts: map[string][]string{
+			"a-only.com": {"203.0.113.10"},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		email    string
+		resolver *fakeResolver
+		want     EmailVerdict
+	}{
+		{"Domain with MX records", "john@example.com", resolver, EmailVerdictDeliverable},
+		{"Domain with multiple MX records", "john@company.test", resolver, EmailVerdictDeliverable},
+		{"Domain with only an A record", "john@a-only.com", resolver, EmailVerdictDeliverable},
+		{"Domain with null MX", "john@nullmx.com", resolver, EmailVerdictUndeliverable},
+		{"Non-existent domain", "john@does-not-exist.com", resolver, EmailVerdictUndeliverable},
+		{"Disposable domain", "john@yopmail.com", resolver, EmailVerdictDisposable},
+		{"Disposable subdomain", "john@eu.mailinator.com", resolver, EmailVerdictDisposable},
+		{"Disposable domain in uppercase", "john@YOPMAIL.COM", resolver, EmailVerdictDisposable},
+		{"Lookup failure", "john@example.com", &fakeResolver{err: &net.DNSError{Err: "i/o timeout", IsTimeout: true}}, EmailVerdictUnknown},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &Service{
+				Cfg:      &config.Config{DisposableEmails: []string{"yopmail.com", " mailinator.com", ""}},
+				Resolver: tt.resolver,
+			}
+
+			if got := service.CheckEmailDeliverability(context.Background(), tt.email); got != tt.want {
+				t.Errorf("CheckEmailDeliverability(%q) = %q, want %q", tt.email, got, tt.want)
+			}
+		})
+	}
+}
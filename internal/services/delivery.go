@@ -0,0 +1,125 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"unicode/utf8"
+
+	"communications/internal/database/dto"
+	"communications/internal/database/models"
+	"communications/internal/metrics"
+	"communications/internal/repository"
+)
+
+// Maximum length of a stored delivery status reason, matching the "status_reason" column.
+const maxReasonLength = 255
+
+// Updates the status of the notification an Event Grid delivery report refers to, and suppresses
+// recipients whose mailbox hard-bounced (even for emails not stored as notifications, e.g. verification emails).
+// Events of other types, malformed reports and reports for messages not stored as notifications (verification
+// messages, keyword answers, forwarded replies, test sends) are logged and skipped, since redelivering them
+// would not help; only storage failures are returned, so Event Grid retries them.
+func (s *Service) ApplyDeliveryReport(ctx context.Context, event dto.EventGridEvent) error {
+	var channel, messageID, recipient, status, reason string
+
+	switch event.EventType {
+	case dto.EmailDeliveryReportEvent:
+		var report dto.EmailDeliveryReport
+		if err := json.Unmarshal(event.Data, &report); err != nil {
+			s.Logger.Warn("Malformed email delivery report", "event_id", event.ID, "error", err)
+			return nil
+		}
+
+		channel, messageID, reason = "email", report.MessageID, deliveryReason(report.Status, report.DeliveryStatusDetails.StatusMessage)
+		recipient, status = report.Recipient, emailStatus(report.Status)
+
+	case dto.SMSDeliveryReportEvent:
+		var report dto.SMSDeliveryReport
+		if err := json.Unmarshal(event.Data, &report); err != nil {
+			s.Logger.Warn("Malformed SMS delivery report", "event_id", event.ID, "error", err)
+			return nil
+		}
+
+		channel, messageID, reason = "sms", report.MessageID, deliveryReason(report.DeliveryStatus, report.DeliveryStatusDetails)
+		status = smsStatus(report.DeliveryStatus)
+
+	default:
+		s.Logger.Debug("Ignoring Event Grid event", "event_id", event.ID, "event_type", event.EventType)
+		return nil
+	}
+
+	if status == "" {
+		return nil
+	}
+
+	if status == models.NotificationBounced {
+		if err := s.suppressBounce(ctx, event, recipient, reason); err != nil {
+			return err
+		}
+	}
+
+	err := s.Notifications.UpdateNotificationStatus(ctx, channel, messageID, status, &reason)
+	if errors.Is(err, repository.ErrNotFound) {
+		metrics.DeliveryReportsTotal.WithLabelValues(channel, "unknown").Inc()
+		s.Logger.Info("Delivery report for an unknown notification", "event_id", event.ID, "channel", channel, "message_id", messageID)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	metrics.DeliveryReportsTotal.WithLabelValues(channel, status).Inc()
+
+	return nil
+}
+
+// Suppresses the recipient of a bounced email, keeping the bounce details as the reason.
+// Reports without a valid recipient are logged and skipped; only storage failures are returned.
+func (s *Service) suppressBounce(ctx context.Context, event dto.EventGridEvent, recipient, reason string) error {
+	if !dto.NormalizeRecipient("email", &recipient) {
+		s.Logger.Warn("Bounce report without a valid recipient", "event_id", event.ID)
+		return nil
+	}
+
+	_, err := s.Suppress(ctx, "email", recipient, models.SuppressionBounce, &reason)
+	return err
+}
+
+// Maps the status of an email delivery report to a notification status.
+// Returns an empty string for intermediate statuses (e.g. Expanded for distribution lists), which are skipped.
+func emailStatus(status string) string {
+	switch status {
+	case "Delivered":
+		return models.NotificationDelivered
+	case "Bounced":
+		return models.NotificationBounced
+	case "Expanded":
+		return ""
+	default: // Failed, Suppressed, Quarantined, FilteredSpam.
+		return models.NotificationFailed
+	}
+}
+
+// Maps the status of an SMS delivery report to a notification status.
+func smsStatus(status string) string {
+	if status == "Delivered" {
+		return models.NotificationDelivered
+	}
+
+	return models.NotificationFailed
+}
+
+// Combines the provider status with its details (e.g. "Bounced: Mailbox does not exist"), limited to maxReasonLength.
+func deliveryReason(status, details string) string {
+	reason := status
+	if details != "" {
+		reason += ": " + details
+	}
+
+	if utf8.RuneCountInString(reason) > maxReasonLength {
+		reason = string([]rune(reason)[:maxReasonLength])
+	}
+
+	return reason
+}
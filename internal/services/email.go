@@ -2,28 +2,40 @@ package services
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"html"
 	"io"
 	"net/http"
 	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
 
 	"communications/internal/database/dto"
+	"communications/internal/metrics"
+	"communications/internal/tracing"
 	"communications/internal/utils"
 )
 
 // Prepares and sends an email notification to the specified recipient.
 // Uses Azure's email API payload structure.
-// Returns an error if the required parameters are missing or if the sending fails.
-func (s *Service) SendEmail(to *string, params *dto.CreateLeadDTO) error {
+// Returns the operation ID used to match delivery reports, or an error if the required parameters are missing
+// or if the sending fails. Suppressed recipients are skipped with ErrSuppressed.
+func (s *Service) SendEmail(ctx context.Context, to *string, params *dto.CreateLeadDTO) (string, error) {
 	if to == nil || params == nil {
-		return errors.New("id and payload are required")
+		return "", errors.New("id and payload are required")
+	}
+
+	if err := s.checkSuppression(ctx, "email", *to); err != nil {
+		return "", err
 	}
 
 	endpoint, key, err := parseACS(s.Cfg.AzureURL)
 	if err != nil {
-		return err
+		return "", err
 	}
 
 	message := dto.EmailMessage{
@@ -35,15 +47,10 @@ func (s *Service) SendEmail(to *string, params *dto.CreateLeadDTO) error {
 
 	payload, err := json.Marshal(message)
 	if err != nil {
-		return err
+		return "", err
 	}
 
-	azureError := sendAzureEmail(endpoint, key, payload)
-	if azureError != nil {
-		return azureError
-	}
-
-	return nil
+	return s.sendAzureEmail(ctx, endpoint, key, payload)
 }
 
 // Parses Azure Connection String
@@ -68,35 +75,55 @@ func parseACS(url string) (endpoint, key string, err error) {
 }
 
 // Sends an email via Azure Communication Services Email REST API.
-func sendAzureEmail(endpoint, key string, payload []byte) error {
+// Returns the ID of the send operation.
+func (s *Service) sendAzureEmail(ctx context.Context, endpoint, key string, payload []byte) (id string, err error) {
+	ctx, span := tracing.Start(ctx, "sendAzureEmail")
+	defer func() {
+		tracing.Fail(span, err)
+		span.End()
+	}()
+
 	url := fmt.Sprintf("%s/emails:send?api-version=2023-03-31", endpoint)
-	req, err := http.NewRequest("POST", url, bytes.NewBuffer(payload))
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(payload))
 	if err != nil {
-		return err
+		return "", err
 	}
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("api-key", key)
 
-	client := &http.Client{}
-	res, err := client.Do(req)
+	start := time.Now()
+
+	res, err := s.HTTPClient.Do(req)
 	if err != nil {
-		return err
+		metrics.ObserveAzure("email", start, 0)
+		s.Logger.Error("Azure Email Service request failed", "error", err)
+		return "", err
 	}
 	defer res.Body.Close()
 
+	metrics.ObserveAzure("email", start, res.StatusCode)
+	span.SetAttributes(attribute.Int("http.response.status_code", res.StatusCode))
+
 	bodyBytes, _ := io.ReadAll(res.Body)
 	resBody := string(bodyBytes)
 
 	if res.StatusCode >= 300 {
-		fmt.Printf("Azure Email Service ~ %s: %s\n", res.Status, resBody)
-		return errors.New("failed to send email: " + res.Status)
+		s.Logger.Error("Azure Email Service request failed", "status", res.Status, "body", resBody)
+		return "", errors.New("failed to send email: " + res.Status)
 	}
 
-	return nil
+	var result dto.EmailSendResult
+	if err := json.Unmarshal(bodyBytes, &result); err != nil || result.ID == "" {
+		s.Logger.Error("Azure Email Service returned no operation ID", "status", res.Status, "body", resBody)
+		return "", errors.New("invalid Azure Email Service response")
+	}
+
+	return result.ID, nil
 }
 
 // Generates the HTML body for the lead notification email.
 // Used to format the email content sent to the client.
+// All user-submitted values are HTML-escaped before being embedded.
 func setHTML(params *dto.CreateLeadDTO) string {
 	message := ""
 	if params != nil && params.Message != nil {
@@ -192,14 +219,49 @@ func setHTML(params *dto.CreateLeadDTO) string {
 				<div class="container">
 					<h1>New Website Lead</h1>
 					<div class="info-container">
-						<p><strong>Name:</strong> ` + params.Name + `</p>
-						<p><strong>Email:</strong> ` + params.Email + `</p>
-						<p><strong>Phone:</strong> ` + params.Phone + `</p>
-						<p><strong>Message:</strong> ` + message + `</p>
-					</div>
+						<p><strong>Name:</strong> ` + html.EscapeString(params.Name) + `</p>
+						<p><strong>Email:</strong> ` + html.EscapeString(params.Email) + `</p>
+						<p><strong>Phone:</strong> ` + html.EscapeString(params.Phone) + `</p>
+						<p><strong>Message:</strong> ` + html.EscapeString(message) + `</p>
+					</div>` + setTrackingHTML(params) + `
 					<p class="footer">This email was sent via the Contact Us form.</p>
 				</div>
 			</body>
 		</html>
 	`
 }
+
+// Generates the HTML section with the lead's campaign tracking details.
+// Only fields provided by the submitter are listed; returns an empty string if there are none.
+func setTrackingHTML(params *dto.CreateLeadDTO) string {
+	fields := []struct {
+		label string
+		value *string
+	}{
+		{"Source", params.UTMSource},
+		{"Medium", params.UTMMedium},
+		{"Campaign", params.UTMCampaign},
+		{"Term", params.UTMTerm},
+		{"Content", params.UTMContent},
+		{"Referrer", params.Referrer},
+		{"Landing Page", params.LandingPage},
+		{"User Agent", params.UserAgent},
+	}
+
+	rows := ""
+	for _, field := range fields {
+		if field.value != nil {
+			rows += `
+						<p><strong>` + field.label + `:</strong> ` + html.EscapeString(*field.value) + `</p>`
+		}
+	}
+
+	if rows == "" {
+		return ""
+	}
+
+	return `
+					<div class="info-container">
+						<h3>Campaign</h3>` + rows + `
+					</div>`
+}
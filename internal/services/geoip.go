@@ -0,0 +1,63 @@
+package services
+
+import (
+	"net"
+
+	"github.com/oschwald/geoip2-golang"
+)
+
+// Resolves IP addresses to a country and city using an offline MaxMind-format database
+// (e.g. GeoLite2-City.mmdb or GeoLite2-Country.mmdb).
+type GeoIP struct {
+	reader *geoip2.Reader
+}
+
+// Opens the GeoIP database at the given path.
+// Called once at startup; the returned GeoIP is safe for concurrent use.
+func OpenGeoIP(path string) (*GeoIP, error) {
+	reader, err := geoip2.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return &GeoIP{reader: reader}, nil
+}
+
+// Looks up the ISO country code and English city name of the IP address.
+// Returns nil values if the lookup is disabled (nil GeoIP), fails, or the database has no data for the IP.
+func (g *GeoIP) Locate(value string) (country, city *string) {
+	ip := net.ParseIP(value)
+	if g == nil || ip == nil {
+		return nil, nil
+	}
+
+	record, err := g.reader.City(ip)
+	if err != nil {
+		// Country-only databases do not support City lookups.
+		countryRecord, err := g.reader.Country(ip)
+		if err != nil {
+			return nil, nil
+		}
+
+		record = &geoip2.City{Country: countryRecord.Country}
+	}
+
+	if code := record.Country.IsoCode; code != "" {
+		country = &code
+	}
+
+	if name := record.City.Names["en"]; name != "" {
+		city = &name
+	}
+
+	return country, city
+}
+
+// Releases the memory-mapped database file.
+func (g *GeoIP) Close() error {
+	if g == nil {
+		return nil
+	}
+
+	return g.reader.Close()
+}
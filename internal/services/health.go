@@ -0,0 +1,120 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"communications/internal/database"
+	"communications/internal/utils"
+)
+
+// Health states of a single component reported by the readiness check.
+const (
+	ComponentUp       = "up"       // The component works as expected.
+	ComponentDegraded = "degraded" // The component is unavailable or misconfigured.
+)
+
+// Age of the oldest pending email operation above which the outbox is reported as degraded.
+const maxBacklogAge = 15 * time.Minute
+
+// Describes the health of a single dependency in the readiness report.
+type ComponentStatus struct {
+	Status   string `json:"status"`            // "up" or "degraded".
+	Critical bool   `json:"critical"`          // Whether a degraded state makes the service unready.
+	Details  string `json:"details,omitempty"` // Additional information (e.g. migration version or error).
+}
+
+// Checks every dependency needed to serve leads: database connectivity, migration state and provider configuration,
+// and reports the backlog of email operations still waiting for a final status (not critical).
+// Returns the per-component report and whether the service is ready (no critical component degraded).
+func (s *Service) CheckReadiness(ctx context.Context) (map[string]ComponentStatus, bool) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	report := map[string]ComponentStatus{
+		"database":   newComponentStatus(true, "", s.Health.Ping(ctx)),
+		"migrations": s.checkMigrations(ctx),
+		"provider":   newComponentStatus(true, "Azure Communication Services", s.checkProvider()),
+		"outbox":     s.checkOutbox(ctx),
+	}
+
+	ready := true
+	for _, component := range report {
+		if component.Critical && component.Status != ComponentUp {
+			ready = false
+		}
+	}
+
+	return report, ready
+}
+
+// Reports the applied migration version; a missing, dirty (half-applied) or outdated version is degraded.
+// Outdated means the binary embeds migrations that have not been applied, e.g. with AUTO_MIGRATE disabled.
+func (s *Service) checkMigrations(ctx context.Context) ComponentStatus {
+	version, dirty, err := s.Health.MigrationVersion(ctx)
+	if err == nil && dirty {
+		err = fmt.Errorf("version %d is dirty", version)
+	}
+	if err == nil {
+		latest, latestErr := database.LatestVersion()
+		if latestErr == nil && version < latest {
+			latestErr = fmt.Errorf("version %d is behind the latest migration %d", version, latest)
+		}
+		err = latestErr
+	}
+
+	return newComponentStatus(true, fmt.Sprintf("version %d", version), err)
+}
+
+// Validates the notification provider settings without calling the provider.
+// Sender addresses are compared case-insensitively (e.g. Azure's default DoNotReply@<id>.azurecomm.net),
+// and SMS senders may be phone numbers, short codes or alphanumeric sender IDs.
+func (s *Service) checkProvider() error {
+	if _, _, err := parseACS(s.Cfg.AzureURL); err != nil {
+		return err
+	}
+
+	if !utils.ValidateEmail(strings.ToLower(s.Cfg.EmailFrom)) {
+		return errors.New("invalid EMAIL_FROM sender address")
+	}
+
+	if !utils.ValidateSMSSender(s.Cfg.SMSFrom) {
+		return errors.New("invalid SMS_FROM sender")
+	}
+
+	return nil
+}
+
+// Reports the email operations still waiting for a final status from the poller, and the age of the oldest.
+// Degraded if the oldest is older than maxBacklogAge (e.g. the poller is stuck or Azure is slow to process).
+func (s *Service) checkOutbox(ctx context.Context) ComponentStatus {
+	count, oldest, err := s.Notifications.OperationBacklog(ctx, time.Now().UTC().Add(-maxOperationAge))
+	if err != nil {
+		return newComponentStatus(false, "", err)
+	}
+
+	if oldest == nil {
+		return newComponentStatus(false, "no pending email operations", nil)
+	}
+
+	age := time.Since(*oldest).Round(time.Second)
+	details := fmt.Sprintf("%d pending email operations, oldest %s", count, age)
+
+	if age > maxBacklogAge {
+		return ComponentStatus{Status: ComponentDegraded, Critical: false, Details: details}
+	}
+
+	return newComponentStatus(false, details, nil)
+}
+
+// Builds a component status from the check's error, using the error message as details when degraded.
+func newComponentStatus(critical bool, details string, err error) ComponentStatus {
+	if err != nil {
+		return ComponentStatus{Status: ComponentDegraded, Critical: critical, Details: err.Error()}
+	}
+
+	return ComponentStatus{Status: ComponentUp, Critical: critical, Details: details}
+}
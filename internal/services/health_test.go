@@ -0,0 +1,79 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"communications/internal/config"
+	"communications/internal/database/models"
+	"communications/internal/repository"
+)
+
+// Checks if the provider configuration is validated without calling the provider.
+func TestCheckProvider(t *testing.T) {
+	valid := config.Config{
+		AzureURL:  "endpoint=https://example.communication.azure.com;accesskey=secret",
+		EmailFrom: "donotreply@example.com",
+		SMSFrom:   "+12345678901",
+	}
+
+	tests := []struct {
+		name    string
+		modify  func(cfg *config.Config)
+		wantErr bool
+	}{
+		{"Valid configuration", func(cfg *config.Config) {}, false},
+		{"Missing access key", func(cfg *config.Config) { cfg.AzureURL = "endpoint=https://example.communication.azure.com" }, true},
+		{"Empty connection string", func(cfg *config.Config) { cfg.AzureURL = "" }, true},
+		{"Invalid sender email", func(cfg *config.Config) { cfg.EmailFrom = "donotreply" }, true},
+		{"Azure default sender email", func(cfg *config.Config) { cfg.EmailFrom = "DoNotReply@0a1b2c3d.azurecomm.net" }, false},
+		{"Invalid sender phone", func(cfg *config.Config) { cfg.SMSFrom = "12-345" }, true},
+		{"Short code sender", func(cfg *config.Config) { cfg.SMSFrom = "12345" }, false},
+		{"Alphanumeric sender", func(cfg *config.Config) { cfg.SMSFrom = "Acme" }, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := valid
+			tt.modify(&cfg)
+
+			err := (&Service{Cfg: &cfg}).checkProvider()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("checkProvider() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// Checks if the outbox reports pending email operations and degrades, without becoming critical, once they are stale.
+func TestCheckOutbox(t *testing.T) {
+	tests := []struct {
+		name    string
+		ages    []time.Duration // Ages of the pending email operations.
+		status  string
+		details string
+	}{
+		{"Empty", nil, ComponentUp, "no pending email operations"},
+		{"Recent", []time.Duration{time.Minute, 2 * time.Minute}, ComponentUp, "2 pending email operations, oldest 2m0s"},
+		{"Stale", []time.Duration{time.Minute, time.Hour}, ComponentDegraded, "2 pending email operations, oldest 1h0m0s"},
+		{"Expired", []time.Duration{48 * time.Hour}, ComponentUp, "no pending email operations"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := repository.NewMemory()
+			for i, age := range tt.ages {
+				messageID := fmt.Sprintf("operation-%d", i)
+				store.CreateNotification(context.Background(), &models.Notification{Channel: "email", MessageID: &messageID, CreatedAt: time.Now().UTC().Add(-age)})
+			}
+
+			status := (&Service{Notifications: store}).checkOutbox(context.Background())
+			if status.Status != tt.status || status.Critical || !strings.HasPrefix(status.Details, tt.details) {
+				t.Errorf("checkOutbox() = %+v, want %s with details %q", status, tt.status, tt.details)
+			}
+		})
+	}
+}
@@ -0,0 +1,326 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"html"
+	"slices"
+	"strings"
+	"time"
+
+	"communications/internal/database/dto"
+	"communications/internal/database/models"
+	"communications/internal/metrics"
+	"communications/internal/repository"
+	"communications/internal/utils"
+)
+
+// Forwarding of client SMS replies to the lead, configured through REPLY_FORWARDING.
+const (
+	ReplyForwardingOff   = "off"   // Replies are only stored.
+	ReplyForwardingEmail = "email" // Replies are forwarded to the lead's email address.
+	ReplyForwardingSMS   = "sms"   // Replies are forwarded to the lead's phone.
+)
+
+// Keywords of inbound SMS that carriers require senders to honor.
+// Matched case-insensitively against the whole message, ignoring surrounding spaces and punctuation.
+var (
+	optOutKeywords = []string{"STOP", "STOPALL", "UNSUBSCRIBE", "CANCEL", "END", "QUIT", "OPTOUT", "REVOKE"}
+	optInKeywords  = []string{"START", "UNSTOP"}
+	helpKeywords   = []string{"HELP", "INFO"}
+)
+
+// Confirmations sent after an opt-out or opt-in keyword.
+const (
+	optOutConfirmation = "You have been unsubscribed and will no longer receive lead notifications. Reply START to resubscribe."
+	optInConfirmation  = "You have been resubscribed to lead notifications. Reply STOP to unsubscribe."
+)
+
+// Maximum length of a stored reply.
+const maxReplyLength = 1600
+
+// Handles an SMSReceived event for a message sent to the sender number.
+// Opt-out keywords suppress the sender, opt-in keywords lift its opt-out and help keywords are answered,
+// once per message ID, so redelivered events are not answered again.
+// Other messages are linked to the lead the sender was last notified about and forwarded per REPLY_FORWARDING.
+// Malformed events and unmatched replies are logged and skipped; only storage failures are returned,
+// so Event Grid retries them.
+func (s *Service) HandleInboundSMS(ctx context.Context, event dto.EventGridEvent) error {
+	var sms dto.SMSReceived
+	if err := json.Unmarshal(event.Data, &sms); err != nil || sms.MessageID == "" {
+		s.Logger.Warn("Malformed inbound SMS", "event_id", event.ID, "error", err)
+		return nil
+	}
+
+	sender := sms.From
+	if !dto.NormalizeRecipient("sms", &sender) {
+		s.Logger.Warn("Inbound SMS without a valid sender", "event_id", event.ID, "message_id", sms.MessageID)
+		return nil
+	}
+
+	keyword := strings.ToUpper(strings.Trim(sms.Message, " \t\r\n.!"))
+
+	switch {
+	case slices.Contains(optOutKeywords, keyword):
+		return s.optOut(ctx, sms.MessageID, sender, keyword)
+	case slices.Contains(optInKeywords, keyword):
+		return s.optIn(ctx, sms.MessageID, sender)
+	case slices.Contains(helpKeywords, keyword):
+		metrics.InboundSMSTotal.WithLabelValues("help").Inc()
+		return s.answerKeyword(ctx, sms.MessageID, sender, s.Cfg.SMSHelpMessage)
+	default:
+		return s.linkReply(ctx, sms, sender)
+	}
+}
+
+// Suppresses the sender's phone after an opt-out keyword and confirms the opt-out.
+// Suppressions added for another reason (e.g. by an admin) are kept as they are, so a later opt-in cannot lift them.
+func (s *Service) optOut(ctx context.Context, messageID, sender, keyword string) error {
+	suppression, err := s.Suppressions.FindSuppression(ctx, "sms", sender)
+	if err != nil && !errors.Is(err, repository.ErrNotFound) {
+		return err
+	}
+
+	if err == nil && suppression.Source != models.SuppressionOptOut {
+		s.Logger.Info("Opt-out of a recipient suppressed for another reason", "recipient", sender, "source", suppression.Source)
+	} else {
+		reason := "Replied " + keyword
+		if _, err := s.Suppress(ctx, "sms", sender, models.SuppressionOptOut, &reason); err != nil {
+			return err
+		}
+	}
+
+	metrics.InboundSMSTotal.WithLabelValues("opt_out").Inc()
+
+	return s.answerKeyword(ctx, messageID, sender, optOutConfirmation)
+}
+
+// Lifts the sender's opt-out after an opt-in keyword and confirms it.
+// Suppressions added for another reason (e.g. by an admin) are kept, and no confirmation is sent then.
+func (s *Service) optIn(ctx context.Context, messageID, sender string) error {
+	metrics.InboundSMSTotal.WithLabelValues("opt_in").Inc()
+
+	suppression, err := s.Suppressions.FindSuppression(ctx, "sms", sender)
+	if err != nil && !errors.Is(err, repository.ErrNotFound) {
+		return err
+	}
+
+	if err == nil {
+		if suppression.Source != models.SuppressionOptOut {
+			s.Logger.Warn("Opt-in of a recipient suppressed for another reason", "recipient", sender, "source", suppression.Source)
+			return nil
+		}
+
+		if err := s.Suppressions.RemoveSuppression(ctx, "sms", sender); err != nil && !errors.Is(err, repository.ErrNotFound) {
+			return err
+		}
+
+		s.Logger.Info("Recipient opted in", "channel", "sms", "recipient", sender)
+	}
+
+	return s.answerKeyword(ctx, messageID, sender, optInConfirmation)
+}
+
+// Answers a keyword SMS unless the message was already processed, since Event Grid may redeliver it.
+// The message is recorded after the keyword took effect, so a failed attempt is still retried.
+func (s *Service) answerKeyword(ctx context.Context, messageID, to, text string) error {
+	err := s.Replies.RecordInboundMessage(ctx, messageID)
+	if errors.Is(err, repository.ErrDuplicate) {
+		s.Logger.Info("Skipping a redelivered keyword SMS", "message_id", messageID)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	s.replySMS(ctx, to, text)
+
+	return nil
+}
+
+// Links a client's reply to the lead of the latest SMS notification sent to the client within REPLY_WINDOW,
+// and forwards it to the lead if REPLY_FORWARDING is enabled. Redelivered replies are skipped.
+// Replies are only forwarded if the client was notified about a single lead within the window, since a reply
+// meant for an earlier lead must never reach another member of the public.
+func (s *Service) linkReply(ctx context.Context, sms dto.SMSReceived, sender string) error {
+	since := time.Now().UTC().Add(-time.Duration(s.Cfg.ReplyWindow) * time.Second)
+
+	recent, err := s.Notifications.RecentNotifications(ctx, "sms", sender, since, 2)
+	if err != nil {
+		return err
+	}
+	if len(recent) == 0 {
+		metrics.InboundSMSTotal.WithLabelValues("unmatched").Inc()
+		s.Logger.Info("Inbound SMS matches no recent lead", "message_id", sms.MessageID, "sender", sender)
+		return nil
+	}
+
+	notification := recent[0]
+	ambiguous := len(recent) > 1 && *recent[1].LeadID != *notification.LeadID
+
+	reply := models.Reply{
+		ClientID:  notification.ClientID,
+		LeadID:    *notification.LeadID,
+		MessageID: sms.MessageID,
+		Sender:    sender,
+		Message:   truncate(utils.SanitizeText(sms.Message), maxReplyLength),
+	}
+
+	err = s.Replies.CreateReply(ctx, &reply)
+	if errors.Is(err, repository.ErrDuplicate) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	metrics.InboundSMSTotal.WithLabelValues("reply").Inc()
+
+	if s.Cfg.ReplyForwarding == ReplyForwardingOff {
+		return nil
+	}
+	if ambiguous {
+		s.Logger.Warn("Not forwarding a reply matching several recent leads", "reply_id", reply.ID, "client_id", reply.ClientID)
+		return nil
+	}
+
+	s.forwardReply(ctx, &reply)
+
+	return nil
+}
+
+// Forwards a reply to its lead through the REPLY_FORWARDING channel, unless the lead is suppressed or
+// the client's quota for the channel is exhausted. The message is reserved against the quota before sending
+// and released if it is not sent.
+// Failures are logged but never returned, since the reply is already stored and a redelivery would skip it.
+func (s *Service) forwardReply(ctx context.Context, reply *models.Reply) {
+	channel := s.Cfg.ReplyForwarding
+	email, sms := channel == ReplyForwardingEmail, channel == ReplyForwardingSMS
+
+	client, err := s.Clients.FindClient(ctx, reply.ClientID)
+	if err != nil {
+		s.Logger.Error("Unable to forward the reply", "reply_id", reply.ID, "lead_id", reply.LeadID, "channel", channel, "error", err)
+		return
+	}
+
+	_, _, err = s.ReserveQuota(ctx, &client, email, sms)
+	if errors.Is(err, ErrQuotaExceeded) {
+		s.Logger.Warn("Not forwarding a reply over the client's quota", "reply_id", reply.ID, "client_id", client.ID, "channel", channel)
+		return
+	}
+	if err != nil {
+		s.Logger.Error("Unable to reserve the quota", "client_id", client.ID, "error", err)
+		return
+	}
+
+	messageID, err := s.sendForward(ctx, channel, &client, reply)
+	if err != nil {
+		s.ReleaseQuota(ctx, client.ID, email, sms)
+		if !errors.Is(err, ErrSuppressed) {
+			s.Logger.Error("Unable to forward the reply", "reply_id", reply.ID, "lead_id", reply.LeadID, "channel", channel, "error", err)
+		}
+		return
+	}
+
+	if err := s.Replies.SetReplyForwarded(ctx, reply.ID, channel, messageID); err != nil {
+		s.Logger.Error("Unable to record the forwarded reply", "reply_id", reply.ID, "error", err)
+	}
+}
+
+// Sends the client's reply to the lead by email (replying to the client's email) or by SMS,
+// and returns the Azure message ID. Suppressed leads are skipped with ErrSuppressed.
+func (s *Service) sendForward(ctx context.Context, channel string, client *models.Client, reply *models.Reply) (string, error) {
+	lead, err := s.Leads.FindLead(ctx, reply.LeadID)
+	if err != nil {
+		return "", err
+	}
+
+	recipient := lead.Email
+	if channel == ReplyForwardingSMS {
+		recipient = lead.Phone
+	}
+
+	if err := s.checkSuppression(ctx, channel, recipient); err != nil {
+		return "", err
+	}
+
+	if channel == ReplyForwardingSMS {
+		return s.sendSMSText(ctx, lead.Phone, client.Name+": "+reply.Message)
+	}
+
+	endpoint, key, err := parseACS(s.Cfg.AzureURL)
+	if err != nil {
+		return "", err
+	}
+
+	email := dto.EmailMessage{
+		SenderAddress: s.Cfg.EmailFrom,
+		Recipients:    dto.EmailRecipients{To: []dto.EmailRecipientAddress{{Address: lead.Email}}},
+		Content:       dto.EmailContent{Subject: "Reply from " + client.Name, HTML: setReplyHTML(client.Name, lead.Name, reply.Message)},
+		ReplyTo:       []dto.EmailRecipientAddress{{Address: client.Email}},
+	}
+
+	payload, err := json.Marshal(email)
+	if err != nil {
+		return "", err
+	}
+
+	return s.sendAzureEmail(ctx, endpoint, key, payload)
+}
+
+// Answers an inbound SMS from the sender number. Failures are logged, since the keyword was already processed.
+func (s *Service) replySMS(ctx context.Context, to, text string) {
+	if _, err := s.sendSMSText(ctx, to, text); err != nil {
+		s.Logger.Error("Unable to answer the inbound SMS", "recipient", to, "error", err)
+	}
+}
+
+// Sends a plain text SMS from SMS_FROM and returns the Azure message ID.
+func (s *Service) sendSMSText(ctx context.Context, to, text string) (string, error) {
+	endpoint, key, err := parseACS(s.Cfg.AzureURL)
+	if err != nil {
+		return "", err
+	}
+
+	payload, err := json.Marshal(dto.SMSMessage{From: s.Cfg.SMSFrom, To: []string{to}, Message: text})
+	if err != nil {
+		return "", err
+	}
+
+	return s.sendAzureSMS(ctx, endpoint, key, payload)
+}
+
+// Limits the text to max runes.
+func truncate(text string, max int) string {
+	if runes := []rune(text); len(runes) > max {
+		return string(runes[:max])
+	}
+
+	return text
+}
+
+// Generates the HTML body of a reply forwarded to the lead by email.
+func setReplyHTML(clientName, leadName, message string) string {
+	return `
+		<!doctype html>
+		<html>
+			<head>
+				<title>Reply from ` + html.EscapeString(clientName) + `</title>
+				<meta name="robots" content="noindex, nofollow" />
+				<meta name="referrer" content="no-referrer" />
+				<meta charset="UTF-8" />
+				<meta name="viewport" content="width=device-width, initial-scale=1" />
+			</head>
+
+			<body style="background: #ffffff; font-family: Arial, sans-serif; margin: 0 auto; padding: 0">
+				<div style="max-width: 600px; margin: 20px auto; padding: 20px">
+					<p style="color: #555">Hello ` + html.EscapeString(leadName) + `,</p>
+					<p style="color: #555">` + html.EscapeString(clientName) + ` replied to your inquiry:</p>
+					<p style="color: #333; border-left: 3px solid #00788a; padding-left: 12px">` + strings.ReplaceAll(html.EscapeString(message), "\n", "<br />") + `</p>
+					<p style="color: #999; font-size: 14px">Reply to this email to answer ` + html.EscapeString(clientName) + ` directly.</p>
+				</div>
+			</body>
+		</html>
+	`
+}
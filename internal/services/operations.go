@@ -0,0 +1,141 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+
+	"communications/internal/database/dto"
+	"communications/internal/metrics"
+	"communications/internal/tracing"
+)
+
+// Email operations older than this are no longer polled, since Azure only keeps them for a limited time.
+const maxOperationAge = 24 * time.Hour
+
+// Maximum number of email operations checked per poll.
+const operationBatchSize = 100
+
+// Status recorded for operations Azure no longer knows about.
+const operationNotFound = "NotFound"
+
+// Status recorded for operations that were still unfinished after maxOperationAge.
+const operationExpired = "Expired"
+
+// Returned when Azure does not know the email operation (e.g. it expired).
+var errOperationNotFound = errors.New("email operation not found")
+
+// Checks the pending Azure email operations and records the final result against their notifications.
+// Each poll claims the least recently checked operations, so running operations and provider errors never starve
+// newer ones; claimed operations are left to other replicas for half the poll interval. Operations still unfinished
+// after maxOperationAge are marked as expired. Returns an error only if the notifications cannot be loaded or updated.
+func (s *Service) PollEmailOperations(ctx context.Context) error {
+	now := time.Now().UTC()
+	since := now.Add(-maxOperationAge)
+
+	expired, err := s.Notifications.ExpireOperations(ctx, since, operationExpired)
+	if err != nil {
+		return err
+	}
+	if expired > 0 {
+		s.Logger.Warn("Email operations expired before finishing", "count", expired)
+	}
+
+	lease := time.Duration(s.Cfg.EmailPollInterval) * time.Second / 2
+
+	pending, err := s.Notifications.ClaimOperations(ctx, since, now.Add(-lease), operationBatchSize)
+	if err != nil || len(pending) == 0 {
+		return err
+	}
+
+	endpoint, key, err := parseACS(s.Cfg.AzureURL)
+	if err != nil {
+		return err
+	}
+
+	for _, notification := range pending {
+		operation, err := s.getEmailOperation(ctx, endpoint, key, *notification.MessageID)
+		if errors.Is(err, errOperationNotFound) {
+			operation.Status = operationNotFound
+		} else if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			s.Logger.Warn("Unable to check the email operation", "notification_id", notification.ID, "operation_id", *notification.MessageID, "error", err)
+			continue
+		}
+
+		var failure *string
+
+		switch operation.Status {
+		case "Succeeded", operationNotFound:
+		case "Failed", "Canceled":
+			message := ""
+			if operation.Error != nil {
+				message = operation.Error.Message
+			}
+
+			reason := deliveryReason(operation.Status, message)
+			failure = &reason
+		default: // NotStarted, Running.
+			continue
+		}
+
+		if err := s.Notifications.CompleteOperation(ctx, notification.ID, operation.Status, failure); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Gets the status of an email send operation via Azure Communication Services Email REST API.
+// Returns errOperationNotFound if Azure does not know the operation.
+func (s *Service) getEmailOperation(ctx context.Context, endpoint, key, id string) (operation dto.EmailOperation, err error) {
+	ctx, span := tracing.Start(ctx, "getEmailOperation")
+	defer func() {
+		tracing.Fail(span, err)
+		span.End()
+	}()
+
+	url := fmt.Sprintf("%s/emails/operations/%s?api-version=2023-03-31", endpoint, url.PathEscape(id))
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return operation, err
+	}
+	req.Header.Set("api-key", key)
+
+	start := time.Now()
+
+	res, err := s.HTTPClient.Do(req)
+	if err != nil {
+		metrics.ObserveAzure("email_operation", start, 0)
+		return operation, err
+	}
+	defer res.Body.Close()
+
+	metrics.ObserveAzure("email_operation", start, res.StatusCode)
+	span.SetAttributes(attribute.Int("http.response.status_code", res.StatusCode))
+
+	bodyBytes, _ := io.ReadAll(res.Body)
+
+	if res.StatusCode == http.StatusNotFound {
+		return operation, errOperationNotFound
+	}
+	if res.StatusCode >= 300 {
+		return operation, errors.New("failed to get the email operation: " + res.Status)
+	}
+
+	if err := json.Unmarshal(bodyBytes, &operation); err != nil {
+		return operation, fmt.Errorf("invalid Azure email operation response: %w", err)
+	}
+
+	return operation, nil
+}
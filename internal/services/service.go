@@ -2,21 +2,53 @@ package services
 
 import (
 	"communications/internal/config"
+	"communications/internal/repository"
 	"context"
+	"log/slog"
+	"net"
+	"net/http"
 	"time"
-
-	"github.com/jackc/pgx/v5/pgxpool"
 )
 
-// Provides access to the database pool and configuration for business logic operations.
+// Looks up the DNS records used to check whether an email domain can receive mail.
+// Satisfied by *net.Resolver; tests can provide a fake implementation.
+type Resolver interface {
+	LookupMX(ctx context.Context, name string) ([]*net.MX, error)
+	LookupHost(ctx context.Context, host string) ([]string, error)
+}
+
+// Provides access to the repositories and configuration for business logic operations.
 type Service struct {
-	Pool *pgxpool.Pool
-	Cfg  *config.Config
+	Cfg           *config.Config
+	Health        repository.HealthRepository
+	Clients       repository.ClientRepository
+	Leads         repository.LeadRepository
+	Usage         repository.UsageRepository
+	Notifications repository.NotificationRepository
+	Suppressions  repository.SuppressionRepository
+	Replies       repository.ReplyRepository
+	Resolver      Resolver
+	GeoIP         *GeoIP
+	Logger        *slog.Logger
+	HTTPClient    *http.Client
 }
 
-// Creates a new Service instance with the provided database pool and config.
-func NewService(db *pgxpool.Pool, cfg *config.Config) *Service {
-	return &Service{Pool: db, Cfg: cfg}
+// Creates a new Service instance with the provided store and config.
+// Every repository is served by the store (Postgres in production, in-memory in tests).
+func NewService(store repository.Store, cfg *config.Config) *Service {
+	return &Service{
+		Cfg:           cfg,
+		Health:        store,
+		Clients:       store,
+		Leads:         store,
+		Usage:         store,
+		Notifications: store,
+		Suppressions:  store,
+		Replies:       store,
+		Resolver:      net.DefaultResolver,
+		Logger:        slog.Default(),
+		HTTPClient:    defaultHTTPClient,
+	}
 }
 
 // Pings the database to verify connectivity.
@@ -24,5 +56,5 @@ func (s *Service) CheckHealth() error {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	return s.Pool.Ping(ctx)
+	return s.Health.Ping(ctx)
 }
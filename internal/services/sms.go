@@ -2,26 +2,37 @@ package services
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
 
 	"communications/internal/database/dto"
+	"communications/internal/metrics"
+	"communications/internal/tracing"
 )
 
 // Prepares and sends an SMS notification to the specified recipient.
 // Uses Azure's SMS API payload structure.
-// Returns an error if required parameters are missing or if sending fails.
-func (s *Service) SendSMS(to *string, params *dto.CreateLeadDTO) error {
+// Returns the message ID used to match delivery reports, or an error if required parameters are missing
+// or if sending fails. Suppressed recipients are skipped with ErrSuppressed.
+func (s *Service) SendSMS(ctx context.Context, to *string, params *dto.CreateLeadDTO) (string, error) {
 	if to == nil || params == nil {
-		return errors.New("id and payload are required")
+		return "", errors.New("id and payload are required")
+	}
+
+	if err := s.checkSuppression(ctx, "sms", *to); err != nil {
+		return "", err
 	}
 
 	endpoint, key, err := parseACS(s.Cfg.AzureURL)
 	if err != nil {
-		return err
+		return "", err
 	}
 
 	from := s.Cfg.SMSFrom
@@ -35,43 +46,63 @@ func (s *Service) SendSMS(to *string, params *dto.CreateLeadDTO) error {
 
 	payload, err := json.Marshal(smsBody)
 	if err != nil {
-		return err
+		return "", err
 	}
 
-	azureError := sendAzureSMS(endpoint, key, payload)
-	if azureError != nil {
-		return azureError
-	}
-
-	return nil
+	return s.sendAzureSMS(ctx, endpoint, key, payload)
 }
 
-// Sends an SMS via Azure Communication Services SMS REST API.
-func sendAzureSMS(endpoint, key string, payload []byte) error {
+// Sends an SMS to a single recipient via Azure Communication Services SMS REST API.
+// Returns the ID of the message, or an error if Azure did not accept it for the recipient.
+func (s *Service) sendAzureSMS(ctx context.Context, endpoint, key string, payload []byte) (id string, err error) {
+	ctx, span := tracing.Start(ctx, "sendAzureSMS")
+	defer func() {
+		tracing.Fail(span, err)
+		span.End()
+	}()
+
 	url := fmt.Sprintf("%s/sms", endpoint)
-	req, err := http.NewRequest("POST", url, bytes.NewBuffer(payload))
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(payload))
 	if err != nil {
-		return err
+		return "", err
 	}
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("api-key", key)
 
-	client := &http.Client{}
-	res, err := client.Do(req)
+	start := time.Now()
+
+	res, err := s.HTTPClient.Do(req)
 	if err != nil {
-		return err
+		metrics.ObserveAzure("sms", start, 0)
+		s.Logger.Error("Azure SMS Service request failed", "error", err)
+		return "", err
 	}
 	defer res.Body.Close()
 
+	metrics.ObserveAzure("sms", start, res.StatusCode)
+	span.SetAttributes(attribute.Int("http.response.status_code", res.StatusCode))
+
 	bodyBytes, _ := io.ReadAll(res.Body)
 	resBody := string(bodyBytes)
 
 	if res.StatusCode >= 300 {
-		fmt.Printf("Azure SMS Service ~ %s: %s\n", res.Status, resBody)
-		return errors.New("failed to send SMS: " + res.Status)
+		s.Logger.Error("Azure SMS Service request failed", "status", res.Status, "body", resBody)
+		return "", errors.New("failed to send SMS: " + res.Status)
+	}
+
+	var response dto.SMSSendResponse
+	if err := json.Unmarshal(bodyBytes, &response); err != nil || len(response.Value) == 0 {
+		s.Logger.Error("Azure SMS Service returned no message ID", "status", res.Status, "body", resBody)
+		return "", errors.New("invalid Azure SMS Service response")
+	}
+
+	result := response.Value[0]
+	if !result.Successful {
+		s.Logger.Error("Azure SMS Service rejected the message", "to", result.To, "error", result.ErrorMessage)
+		return "", errors.New("failed to send SMS: " + result.ErrorMessage)
 	}
 
-	return nil
+	return result.MessageID, nil
 }
 
 // Generates the Textual message content for the lead notification.
@@ -0,0 +1,53 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	"communications/internal/database/dto"
+	"communications/internal/database/models"
+	"communications/internal/repository"
+)
+
+// Returned in place of a send error for recipients on the suppression list.
+var ErrSuppressed = errors.New("recipient is suppressed")
+
+// Adds a recipient to the suppression list, so lead notifications are no longer sent to it.
+// The recipient is normalized first; suppressing it again replaces the reason and source.
+func (s *Service) Suppress(ctx context.Context, channel, recipient, source string, reason *string) (models.Suppression, error) {
+	suppression := models.Suppression{Channel: channel, Recipient: recipient, Reason: reason, Source: source}
+
+	if !dto.NormalizeRecipient(channel, &suppression.Recipient) {
+		return suppression, errors.New("invalid recipient: " + recipient)
+	}
+
+	if err := s.Suppressions.AddSuppression(ctx, &suppression); err != nil {
+		return suppression, err
+	}
+
+	s.Logger.Info("Recipient suppressed", "channel", channel, "recipient", suppression.Recipient, "source", source)
+
+	return suppression, nil
+}
+
+// Checks the suppression list before a notification is sent to the recipient.
+// Returns ErrSuppressed if the recipient is suppressed. Lookup failures are logged and allow the notification,
+// so a storage outage never blocks leads.
+func (s *Service) checkSuppression(ctx context.Context, channel, recipient string) error {
+	if !dto.NormalizeRecipient(channel, &recipient) {
+		return nil
+	}
+
+	_, err := s.Suppressions.FindSuppression(ctx, channel, recipient)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		s.Logger.Error("Unable to check the suppression list", "channel", channel, "error", err)
+		return nil
+	}
+
+	s.Logger.Info("Skipping a suppressed recipient", "channel", channel, "recipient", recipient)
+
+	return ErrSuppressed
+}
@@ -0,0 +1,78 @@
+package services
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"communications/internal/config"
+	"communications/internal/database/dto"
+	"communications/internal/repository"
+)
+
+// Checks if the Azure calls are traced as children of the request span, with failures marked as errors.
+func TestAzureSpans(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(provider)
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+
+	azure := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/sms" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.WriteHeader(http.StatusAccepted)
+		w.Write([]byte(`{"id": "operation-1", "status": "Running"}`))
+	}))
+	defer azure.Close()
+
+	service := NewService(repository.NewMemory(), &config.Config{AzureURL: "endpoint=" + azure.URL + ";accesskey=secret"})
+	to := "client@example.com"
+	phone := "+12345678901"
+	body := &dto.CreateLeadDTO{Name: "John Doe", Email: "john@example.com", Phone: "+12345678902"}
+
+	ctx, parent := otel.Tracer("test").Start(context.Background(), "LeadHandler")
+	if _, err := service.SendEmail(ctx, &to, body); err != nil {
+		t.Fatalf("SendEmail() error = %v", err)
+	}
+	if _, err := service.SendSMS(ctx, &phone, body); err == nil {
+		t.Fatalf("SendSMS() error = nil, want the Azure failure")
+	}
+	parent.End()
+
+	spans := map[string]tracetest.SpanStub{}
+	for _, span := range exporter.GetSpans() {
+		spans[span.Name] = span
+	}
+
+	tests := []struct {
+		name   string
+		status codes.Code
+	}{
+		{"sendAzureEmail", codes.Unset},
+		{"sendAzureSMS", codes.Error},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			span, ok := spans[tt.name]
+			if !ok {
+				t.Fatalf("span %q was not recorded", tt.name)
+			}
+			if span.Parent.SpanID() != parent.SpanContext().SpanID() {
+				t.Errorf("span %q is not a child of the request span", tt.name)
+			}
+			if span.Status.Code != tt.status {
+				t.Errorf("span %q status = %v, want %v", tt.name, span.Status.Code, tt.status)
+			}
+		})
+	}
+}
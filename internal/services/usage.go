@@ -0,0 +1,116 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"communications/internal/database/models"
+)
+
+// Quota enforcement modes, configured through QUOTA_MODE.
+const (
+	QuotaModeBlock   = "block"   // Rejects the lead once any channel's quota is exhausted.
+	QuotaModeDegrade = "degrade" // Skips exhausted channels (e.g. email-only) and rejects only when all are exhausted.
+)
+
+// Returned in place of a send error for channels skipped because the client's quota is exhausted.
+var ErrQuotaExceeded = errors.New("monthly quota exceeded")
+
+// Loads the client's usage for the current billing period together with its effective quotas.
+// Clients without a custom quota use the configured defaults, where 0 means unlimited.
+func (s *Service) GetUsage(ctx context.Context, client *models.Client) (models.Usage, error) {
+	usage, err := s.Usage.CurrentUsage(ctx, client.ID)
+
+	usage.ClientName = client.Name
+	usage.EmailQuota = quota(client.EmailQuota, s.Cfg.EmailQuota)
+	usage.SMSQuota = quota(client.SMSQuota, s.Cfg.SMSQuota)
+
+	return usage, err
+}
+
+// Reserves the wanted channels (email, sms) for the client's next notification against its monthly quotas.
+// Reservations are atomic, so concurrent leads never exceed a quota; channels that end up not sent must be
+// released with ReleaseQuota. Under QUOTA_MODE=block the lead is rejected unless every wanted channel can be
+// reserved, while degrade mode skips exhausted channels. Returns ErrQuotaExceeded if nothing may be sent.
+func (s *Service) ReserveQuota(ctx context.Context, client *models.Client, email, sms bool) (reservedEmail, reservedSMS bool, err error) {
+	emailQuota := quota(client.EmailQuota, s.Cfg.EmailQuota)
+	smsQuota := quota(client.SMSQuota, s.Cfg.SMSQuota)
+
+	if s.Cfg.QuotaMode == QuotaModeBlock {
+		reserved, err := s.Usage.ReserveUsage(ctx, client.ID, count(email), count(sms), emailQuota, smsQuota)
+		if err != nil {
+			return false, false, err
+		}
+
+		reservedEmail, reservedSMS = reserved && email, reserved && sms
+	} else {
+		if email {
+			if reservedEmail, err = s.Usage.ReserveUsage(ctx, client.ID, 1, 0, emailQuota, nil); err != nil {
+				return false, false, err
+			}
+		}
+
+		if sms {
+			if reservedSMS, err = s.Usage.ReserveUsage(ctx, client.ID, 0, 1, nil, smsQuota); err != nil {
+				s.ReleaseQuota(ctx, client.ID, reservedEmail, false)
+				return false, false, err
+			}
+		}
+	}
+
+	if !reservedEmail && !reservedSMS {
+		return false, false, ErrQuotaExceeded
+	}
+
+	return reservedEmail, reservedSMS, nil
+}
+
+// Releases channels reserved with ReserveQuota that were not sent (e.g. the send failed).
+// Failures are logged, since they only leave the client with a slightly lower remaining quota.
+func (s *Service) ReleaseQuota(ctx context.Context, clientID string, email, sms bool) {
+	if !email && !sms {
+		return
+	}
+
+	if err := s.Usage.AddUsage(ctx, clientID, -count(email), -count(sms)); err != nil {
+		s.Logger.Error("Unable to release the reserved usage", "client_id", clientID, "error", err)
+	}
+}
+
+// Lists the usage of every client during the billing period that contains the given month.
+// Clients without notifications in the period are included with zero counters.
+func (s *Service) UsageReport(ctx context.Context, month time.Time) ([]models.Usage, error) {
+	report, err := s.Usage.ListUsage(ctx, month)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range report {
+		report[i].EmailQuota = quota(report[i].EmailQuota, s.Cfg.EmailQuota)
+		report[i].SMSQuota = quota(report[i].SMSQuota, s.Cfg.SMSQuota)
+	}
+
+	return report, nil
+}
+
+// Converts a channel flag into the number of notifications it counts for.
+func count(send bool) int {
+	if send {
+		return 1
+	}
+
+	return 0
+}
+
+// Resolves the effective quota: the client's own quota if set, otherwise the default (0 means unlimited).
+func quota(custom *int, fallback int) *int {
+	if custom != nil {
+		return custom
+	}
+	if fallback > 0 {
+		return &fallback
+	}
+
+	return nil
+}
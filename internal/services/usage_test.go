@@ -0,0 +1,115 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"communications/internal/config"
+	"communications/internal/database/models"
+	"communications/internal/repository"
+)
+
+// Convert an int to a pointer
+func intPtr(i int) *int {
+	return &i
+}
+
+// Checks if exhausted quotas disable channels or reject the lead, depending on the quota mode,
+// and if only the reserved channels are counted.
+func TestReserveQuota(t *testing.T) {
+	tests := []struct {
+		name       string
+		mode       string
+		used       [2]int // Emails and SMS already sent this month.
+		emailQuota *int
+		smsQuota   *int
+		wantEmail  bool
+		wantSMS    bool
+		wantErr    bool
+	}{
+		{"Unlimited quotas", QuotaModeDegrade, [2]int{500, 500}, nil, nil, true, true, false},
+		{"Within quotas", QuotaModeBlock, [2]int{9, 9}, intPtr(10), intPtr(10), true, true, false},
+		{"SMS exhausted degrades to email", QuotaModeDegrade, [2]int{3, 10}, nil, intPtr(10), true, false, false},
+		{"Email exhausted degrades to SMS", QuotaModeDegrade, [2]int{10, 3}, intPtr(10), nil, false, true, false},
+		{"SMS exhausted blocks", QuotaModeBlock, [2]int{3, 10}, nil, intPtr(10), false, false, true},
+		{"Both exhausted", QuotaModeDegrade, [2]int{10, 10}, intPtr(10), intPtr(10), false, false, true},
+		{"Zero SMS quota", QuotaModeDegrade, [2]int{0, 0}, nil, intPtr(0), true, false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			service := newUsageService(tt.mode)
+			client := &models.Client{ID: "client", EmailQuota: tt.emailQuota, SMSQuota: tt.smsQuota}
+			service.Usage.AddUsage(ctx, client.ID, tt.used[0], tt.used[1])
+
+			sendEmail, sendSMS, err := service.ReserveQuota(ctx, client, true, true)
+			if (err != nil) != tt.wantErr || (err != nil && !errors.Is(err, ErrQuotaExceeded)) {
+				t.Fatalf("ReserveQuota() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if sendEmail != tt.wantEmail || sendSMS != tt.wantSMS {
+				t.Errorf("ReserveQuota() = (%v, %v), want (%v, %v)", sendEmail, sendSMS, tt.wantEmail, tt.wantSMS)
+			}
+
+			usage, _ := service.Usage.CurrentUsage(ctx, client.ID)
+			if usage.Emails != tt.used[0]+count(tt.wantEmail) || usage.SMS != tt.used[1]+count(tt.wantSMS) {
+				t.Errorf("usage = %d emails, %d SMS after the reservation", usage.Emails, usage.SMS)
+			}
+		})
+	}
+}
+
+// Checks if concurrent reservations never exceed the quota, and released reservations can be used again.
+func TestReserveQuotaConcurrently(t *testing.T) {
+	ctx := context.Background()
+	service := newUsageService(QuotaModeDegrade)
+	client := &models.Client{ID: "client", EmailQuota: intPtr(5), SMSQuota: intPtr(0)}
+
+	var reserved atomic.Int32
+	var wg sync.WaitGroup
+
+	for range 20 {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+			if email, _, err := service.ReserveQuota(ctx, client, true, false); err == nil && email {
+				reserved.Add(1)
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	if reserved.Load() != 5 {
+		t.Fatalf("reserved emails = %d, want 5", reserved.Load())
+	}
+
+	service.ReleaseQuota(ctx, client.ID, true, false)
+
+	if email, _, err := service.ReserveQuota(ctx, client, true, false); err != nil || !email {
+		t.Errorf("ReserveQuota() after a release = %v, %v, want the email reserved", email, err)
+	}
+}
+
+// Creates a service with in-memory usage and the given quota mode.
+func newUsageService(mode string) *Service {
+	return &Service{Cfg: &config.Config{QuotaMode: mode}, Usage: repository.NewMemory(), Logger: slog.Default()}
+}
+
+// Checks if the client's own quota takes precedence over the default.
+func TestQuota(t *testing.T) {
+	if got := quota(intPtr(5), 100); got == nil || *got != 5 {
+		t.Errorf("quota(5, 100) = %v, want 5", got)
+	}
+	if got := quota(nil, 100); got == nil || *got != 100 {
+		t.Errorf("quota(nil, 100) = %v, want 100", got)
+	}
+	if got := quota(nil, 0); got != nil {
+		t.Errorf("quota(nil, 0) = %v, want nil (unlimited)", *got)
+	}
+}
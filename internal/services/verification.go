@@ -0,0 +1,305 @@
+package services
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html"
+	"math"
+	"math/big"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"communications/internal/database/dto"
+	"communications/internal/database/models"
+)
+
+// Handling of unverified client contacts, configured through UNVERIFIED_MODE.
+const (
+	UnverifiedModeBlock = "block" // Skips unverified contacts and rejects the lead when no contact is verified.
+	UnverifiedModeWarn  = "warn"  // Notifies unverified contacts anyway and logs a warning.
+)
+
+// Number of digits of SMS verification codes.
+const codeDigits = 6
+
+// Attempts allowed to enter an SMS verification code before a new one must be requested.
+const maxCodeAttempts = 5
+
+var (
+	// Returned in place of a send error for channels skipped because the client's contact is not verified.
+	ErrUnverified = errors.New("contact not verified")
+
+	// Returned when an email verification link was tampered with, has expired or no longer matches the email.
+	ErrInvalidLink = errors.New("verification link is invalid or expired")
+
+	// Returned when an SMS verification code is wrong, has expired or was never sent.
+	ErrInvalidCode = errors.New("verification code is invalid or expired")
+
+	// Returned when the pending SMS verification code was guessed too many times.
+	ErrTooManyAttempts = errors.New("too many attempts, request a new verification code")
+)
+
+// Sends a signed, expiring verification link by email and a one-time code by SMS to the client's unverified contacts.
+// Used when a client is created (and to resend verification); verified contacts are skipped and reported as nil,
+// and suppressed contacts are skipped with ErrSuppressed.
+func (s *Service) SendVerification(ctx context.Context, client *models.Client) (emailError, smsError error) {
+	if !client.EmailVerified {
+		emailError = s.sendVerificationEmail(ctx, client)
+	}
+
+	if !client.PhoneVerified {
+		smsError = s.sendVerificationCode(ctx, client)
+	}
+
+	return emailError, smsError
+}
+
+// Builds the link confirming the client's email, valid until expires.
+// The signature covers the client ID, the email and the expiry, so changing any of them invalidates the link.
+func (s *Service) VerificationLink(client *models.Client, expires time.Time) string {
+	unix := strconv.FormatInt(expires.Unix(), 10)
+
+	query := url.Values{
+		"expires":   {unix},
+		"signature": {s.sign("email", client.ID, client.Email, unix)},
+	}
+
+	return fmt.Sprintf("%s/api/v1/clients/%s/verify/email?%s", s.Cfg.PublicURL, client.ID, query.Encode())
+}
+
+// Checks the signature and expiry of an email verification link without verifying anything.
+// Returns ErrInvalidLink if the link is not valid for the client's current email.
+func (s *Service) CheckLink(client *models.Client, expires, signature string) error {
+	unix, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil || time.Now().Unix() > unix {
+		return ErrInvalidLink
+	}
+
+	if !hmac.Equal([]byte(signature), []byte(s.sign("email", client.ID, client.Email, expires))) {
+		return ErrInvalidLink
+	}
+
+	return nil
+}
+
+// Checks the signature and expiry of an email verification link and marks the client's email as verified.
+// Returns ErrInvalidLink if the link is not valid for the client's current email.
+func (s *Service) VerifyEmail(ctx context.Context, client *models.Client, expires, signature string) error {
+	if err := s.CheckLink(client, expires, signature); err != nil {
+		return err
+	}
+
+	if client.EmailVerified {
+		return nil
+	}
+
+	return s.Clients.VerifyEmail(ctx, client.ID, client.Email)
+}
+
+// Checks the code the client received by SMS and marks its phone as verified.
+// Every attempt is counted, and the code stops working after maxCodeAttempts wrong guesses.
+func (s *Service) VerifyPhone(ctx context.Context, client *models.Client, code string) error {
+	if client.PhoneVerified {
+		return nil
+	}
+
+	if client.PhoneCode == nil || client.PhoneCodeExpiresAt == nil || time.Now().After(*client.PhoneCodeExpiresAt) {
+		return ErrInvalidCode
+	}
+
+	attempts, err := s.Clients.AddPhoneCodeAttempt(ctx, client.ID)
+	if err != nil {
+		return err
+	}
+	if attempts > maxCodeAttempts {
+		return ErrTooManyAttempts
+	}
+
+	if !hmac.Equal([]byte(*client.PhoneCode), []byte(s.sign("phone", client.ID, client.Phone, code))) {
+		return ErrInvalidCode
+	}
+
+	return s.Clients.VerifyPhone(ctx, client.ID, client.Phone)
+}
+
+// Decides which of the client's contacts may be notified under the configured UNVERIFIED_MODE.
+// Returns ErrUnverified for each contact to skip; in warn mode unverified contacts are only logged.
+func (s *Service) CheckVerification(client *models.Client) (emailError, smsError error) {
+	if client.EmailVerified && client.PhoneVerified {
+		return nil, nil
+	}
+
+	if s.Cfg.UnverifiedMode == UnverifiedModeWarn {
+		s.Logger.Warn("Notifying unverified client contacts", "client_id", client.ID, "email_verified", client.EmailVerified, "phone_verified", client.PhoneVerified)
+		return nil, nil
+	}
+
+	if !client.EmailVerified {
+		emailError = ErrUnverified
+	}
+	if !client.PhoneVerified {
+		smsError = ErrUnverified
+	}
+
+	return emailError, smsError
+}
+
+// Emails the verification link to the client, unless its email is suppressed.
+func (s *Service) sendVerificationEmail(ctx context.Context, client *models.Client) error {
+	endpoint, key, err := parseACS(s.Cfg.AzureURL)
+	if err != nil {
+		return err
+	}
+
+	if err := s.checkSuppression(ctx, "email", client.Email); err != nil {
+		return err
+	}
+
+	ttl := time.Duration(s.Cfg.LinkTTL) * time.Second
+	link := s.VerificationLink(client, time.Now().Add(ttl))
+
+	message := dto.EmailMessage{
+		SenderAddress: s.Cfg.EmailFrom,
+		Recipients:    dto.EmailRecipients{To: []dto.EmailRecipientAddress{{Address: client.Email}}},
+		Content:       dto.EmailContent{Subject: "Verify your email address", HTML: setVerificationHTML(client.Name, link, ttl)},
+	}
+
+	payload, err := json.Marshal(message)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.sendAzureEmail(ctx, endpoint, key, payload)
+	return err
+}
+
+// Generates a new code, stores its hash with the expiry and sends the code to the client by SMS.
+// Replaces any pending code, so only the latest one works. Suppressed phones (e.g. after STOP) get no code.
+func (s *Service) sendVerificationCode(ctx context.Context, client *models.Client) error {
+	endpoint, key, err := parseACS(s.Cfg.AzureURL)
+	if err != nil {
+		return err
+	}
+
+	if err := s.checkSuppression(ctx, "sms", client.Phone); err != nil {
+		return err
+	}
+
+	code, err := newCode()
+	if err != nil {
+		return err
+	}
+
+	ttl := time.Duration(s.Cfg.CodeTTL) * time.Second
+	hash := s.sign("phone", client.ID, client.Phone, code)
+
+	if err := s.Clients.SetPhoneCode(ctx, client.ID, hash, time.Now().UTC().Add(ttl)); err != nil {
+		return err
+	}
+
+	message := dto.SMSMessage{
+		From:    s.Cfg.SMSFrom,
+		To:      []string{client.Phone},
+		Message: fmt.Sprintf("Your verification code is %s. It expires in %s.", code, formatTTL(ttl)),
+	}
+
+	payload, err := json.Marshal(message)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.sendAzureSMS(ctx, endpoint, key, payload)
+	return err
+}
+
+// Signs the parts with VERIFICATION_SECRET using HMAC-SHA256 and returns the hex-encoded signature.
+// Used both for verification links and for storing SMS codes without keeping them in plain text.
+func (s *Service) sign(parts ...string) string {
+	mac := hmac.New(sha256.New, []byte(s.Cfg.VerificationSecret))
+	mac.Write([]byte(strings.Join(parts, "\n")))
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Generates a random numeric code of codeDigits digits.
+func newCode() (string, error) {
+	n, err := rand.Int(rand.Reader, big.NewInt(int64(math.Pow10(codeDigits))))
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("%0*d", codeDigits, n.Int64()), nil
+}
+
+// Formats a lifetime in whole hours when possible, otherwise in minutes (e.g. "48 hours", "15 minutes").
+func formatTTL(ttl time.Duration) string {
+	if ttl >= time.Hour && ttl%time.Hour == 0 {
+		return fmt.Sprintf("%d hours", int(ttl.Hours()))
+	}
+
+	return fmt.Sprintf("%d minutes", int(ttl.Minutes()))
+}
+
+// Generates the HTML body of the email verification message.
+func setVerificationHTML(name, link string, ttl time.Duration) string {
+	return `
+		<!doctype html>
+		<html>
+			<head>
+				<title>Verify your email address</title>
+				<meta name="robots" content="noindex, nofollow" />
+				<meta name="referrer" content="no-referrer" />
+				<meta charset="UTF-8" />
+				<meta name="viewport" content="width=device-width, initial-scale=1" />
+			</head>
+
+			<body style="background: #ffffff; font-family: Arial, sans-serif; margin: 0 auto; padding: 0">
+				<div style="max-width: 600px; margin: 20px auto; padding: 20px">
+					<h1 style="text-align: center; color: #333">Verify your email address</h1>
+					<p style="color: #555">Hello ` + html.EscapeString(name) + `,</p>
+					<p style="color: #555">Confirm this address to start receiving website leads by email.</p>
+					<p style="text-align: center"><a href="` + html.EscapeString(link) + `" style="color: #00788a">Verify email address</a></p>
+					<p style="color: #999; font-size: 14px">The link expires in ` + formatTTL(ttl) + `. If you did not expect this email, you can ignore it.</p>
+				</div>
+			</body>
+		</html>
+	`
+}
+
+// Generates the HTML page opened from the verification link, asking the client to confirm with a POST.
+// Link scanners only fetch the page, so the email is verified only once the form is submitted.
+func ConfirmationHTML(name, expires, signature string) string {
+	return `
+		<!doctype html>
+		<html>
+			<head>
+				<title>Verify your email address</title>
+				<meta name="robots" content="noindex, nofollow" />
+				<meta name="referrer" content="no-referrer" />
+				<meta charset="UTF-8" />
+				<meta name="viewport" content="width=device-width, initial-scale=1" />
+			</head>
+
+			<body style="background: #ffffff; font-family: Arial, sans-serif; margin: 0 auto; padding: 0">
+				<div style="max-width: 600px; margin: 20px auto; padding: 20px">
+					<h1 style="text-align: center; color: #333">Verify your email address</h1>
+					<p style="color: #555">Hello ` + html.EscapeString(name) + `,</p>
+					<p style="color: #555">Confirm this address to start receiving website leads by email.</p>
+					<form method="post" style="text-align: center">
+						<input type="hidden" name="expires" value="` + html.EscapeString(expires) + `" />
+						<input type="hidden" name="signature" value="` + html.EscapeString(signature) + `" />
+						<button type="submit" style="background: #00788a; color: #ffffff; border: 0; padding: 10px 20px">Verify email address</button>
+					</form>
+				</div>
+			</body>
+		</html>
+	`
+}
@@ -0,0 +1,75 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Name reported as the service and instrumentation scope of every span.
+const ServiceName = "communications"
+
+// Span exporters, configured through TRACING_EXPORTER.
+const (
+	ExporterNone   = "none"   // Tracing is disabled (spans are no-ops).
+	ExporterOTLP   = "otlp"   // Spans are sent to an OTLP/HTTP collector (see OTEL_EXPORTER_OTLP_ENDPOINT).
+	ExporterStdout = "stdout" // Spans are printed to stdout, useful for local debugging.
+)
+
+// Configures the global tracer provider with the selected exporter.
+// The OTLP exporter honors the standard OTEL_EXPORTER_OTLP_* environment variables (endpoint, headers, etc.).
+// Returns a function that flushes and stops the provider, to be called on shutdown.
+func Init(ctx context.Context, exporter string) (func(context.Context) error, error) {
+	var spanExporter sdktrace.SpanExporter
+	var err error
+
+	switch exporter {
+	case "", ExporterNone:
+		return func(context.Context) error { return nil }, nil
+	case ExporterOTLP:
+		spanExporter, err = otlptracehttp.New(ctx)
+	case ExporterStdout:
+		spanExporter, err = stdouttrace.New(stdouttrace.WithWriter(os.Stdout))
+	default:
+		return nil, fmt.Errorf("unknown tracing exporter %q", exporter)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(spanExporter),
+		sdktrace.WithResource(resource.NewSchemaless(semconv.ServiceName(ServiceName))),
+	)
+
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	return provider.Shutdown, nil
+}
+
+// Starts a span named after the operation, as a child of the span in the context (if any).
+// Looks up the global provider on every call, so tests can install their own provider.
+func Start(ctx context.Context, name string, attributes ...trace.SpanStartOption) (context.Context, trace.Span) {
+	return otel.Tracer(ServiceName).Start(ctx, name, attributes...)
+}
+
+// Records the error on the span and marks it as failed; does nothing if err is nil.
+func Fail(span trace.Span, err error) {
+	if err == nil {
+		return
+	}
+
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+}
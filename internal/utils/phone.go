@@ -0,0 +1,117 @@
+package utils
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Describes the numbering plan of a single country.
+// Used to convert national phone numbers to E.164 and to validate their length.
+type PhonePlan struct {
+	CallingCode string // Country calling code without the leading plus sign.
+	TrunkPrefix string // Prefix dialed before national numbers, stripped during normalization.
+	MinLength   int    // Minimum length of the national significant number.
+	MaxLength   int    // Maximum length of the national significant number.
+}
+
+// Numbering plans keyed by ISO 3166-1 alpha-2 country code.
+// Countries that are not listed only accept numbers in international format.
+var phonePlans = map[string]PhonePlan{
+	"US": {CallingCode: "1", TrunkPrefix: "1", MinLength: 10, MaxLength: 10},
+	"CA": {CallingCode: "1", TrunkPrefix: "1", MinLength: 10, MaxLength: 10},
+	"GB": {CallingCode: "44", TrunkPrefix: "0", MinLength: 9, MaxLength: 10},
+	"IE": {CallingCode: "353", TrunkPrefix: "0", MinLength: 7, MaxLength: 9},
+	"DE": {CallingCode: "49", TrunkPrefix: "0", MinLength: 6, MaxLength: 13},
+	"AT": {CallingCode: "43", TrunkPrefix: "0", MinLength: 4, MaxLength: 13},
+	"CH": {CallingCode: "41", TrunkPrefix: "0", MinLength: 9, MaxLength: 9},
+	"FR": {CallingCode: "33", TrunkPrefix: "0", MinLength: 9, MaxLength: 9},
+	"NL": {CallingCode: "31", TrunkPrefix: "0", MinLength: 9, MaxLength: 9},
+	"IT": {CallingCode: "39", TrunkPrefix: "", MinLength: 6, MaxLength: 11},
+	"ES": {CallingCode: "34", TrunkPrefix: "", MinLength: 9, MaxLength: 9},
+	"RS": {CallingCode: "381", TrunkPrefix: "0", MinLength: 8, MaxLength: 9},
+	"HR": {CallingCode: "385", TrunkPrefix: "0", MinLength: 8, MaxLength: 9},
+	"BA": {CallingCode: "387", TrunkPrefix: "0", MinLength: 8, MaxLength: 8},
+	"ME": {CallingCode: "382", TrunkPrefix: "0", MinLength: 8, MaxLength: 8},
+	"MK": {CallingCode: "389", TrunkPrefix: "0", MinLength: 8, MaxLength: 8},
+	"SI": {CallingCode: "386", TrunkPrefix: "0", MinLength: 8, MaxLength: 8},
+	"AU": {CallingCode: "61", TrunkPrefix: "0", MinLength: 9, MaxLength: 9},
+	"IN": {CallingCode: "91", TrunkPrefix: "0", MinLength: 10, MaxLength: 10},
+}
+
+// Numbering plans keyed by calling code, used to validate numbers in international format.
+// Countries sharing a calling code (e.g. US and CA) are merged into the widest length range.
+var callingCodePlans = plansByCallingCode(phonePlans)
+
+// Longest calling code in digits, so lookups try at most this many leading digits.
+const maxCallingCodeLength = 3
+
+var (
+	phoneFormattingRegExp = regexp.MustCompile(`[\s().\-/]`)
+	digitsRegExp          = regexp.MustCompile(`^\d{1,15}$`)
+)
+
+// Strips formatting from the input phone number and converts it to E.164 (e.g. +381641234567).
+// National numbers (without + or 00) are resolved using the numbering plan of the given country.
+// Used to accept human-typed numbers such as "(555) 123-4567" while storing a single canonical format.
+func NormalizePhoneNumber(phone *string, country string) bool {
+	if phone == nil {
+		return false
+	}
+
+	value := phoneFormattingRegExp.ReplaceAllString(*phone, "")
+	plan, hasPlan := phonePlans[strings.ToUpper(country)]
+
+	var digits string
+
+	switch {
+	case strings.HasPrefix(value, "+"):
+		digits = strings.TrimPrefix(value, "+")
+	case strings.HasPrefix(value, "00"):
+		digits = strings.TrimPrefix(value, "00")
+	case hasPlan:
+		digits = plan.CallingCode + strings.TrimPrefix(value, plan.TrunkPrefix)
+	default:
+		return false
+	}
+
+	if !digitsRegExp.MatchString(digits) || !validatePhonePlan(digits) {
+		return false
+	}
+
+	*phone = "+" + digits
+
+	return true
+}
+
+// Checks the length of the national significant number against the plan of its calling code.
+// The longest matching calling code wins, so the result never depends on map iteration order.
+// Numbers with an unknown calling code fall back to the generic E.164 format check.
+func validatePhonePlan(digits string) bool {
+	for length := min(maxCallingCodeLength, len(digits)); length > 0; length-- {
+		plan, ok := callingCodePlans[digits[:length]]
+		if !ok {
+			continue
+		}
+
+		national := len(digits) - length
+
+		return national >= plan.MinLength && national <= plan.MaxLength
+	}
+
+	return ValidatePhoneNumber("+" + digits)
+}
+
+// Indexes the country plans by calling code, merging countries that share one.
+func plansByCallingCode(plans map[string]PhonePlan) map[string]PhonePlan {
+	indexed := map[string]PhonePlan{}
+
+	for _, plan := range plans {
+		if existing, ok := indexed[plan.CallingCode]; ok {
+			plan.MinLength = min(plan.MinLength, existing.MinLength)
+			plan.MaxLength = max(plan.MaxLength, existing.MaxLength)
+		}
+		indexed[plan.CallingCode] = plan
+	}
+
+	return indexed
+}
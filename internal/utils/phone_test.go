@@ -0,0 +1,99 @@
+package utils
+
+import (
+	"testing"
+)
+
+// Checks if the input phone number is normalized to E.164 using the country's numbering plan.
+func TestNormalizePhoneNumber(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   *string
+		country string
+		want    bool
+		output  string
+	}{
+		{"Already in E.164", strPtr("+12345678901"), "", true, "+12345678901"},
+		{"International with spaces", strPtr("+381 64 123 4567"), "", true, "+381641234567"},
+		{"International with 00 prefix", strPtr("00381641234567"), "", true, "+381641234567"},
+		{"US national with formatting", strPtr("(555) 123-4567"), "US", true, "+15551234567"},
+		{"US national with trunk prefix", strPtr("1-555-123-4567"), "US", true, "+15551234567"},
+		{"Serbian national mobile", strPtr("0641234567"), "RS", true, "+381641234567"},
+		{"Lowercase country code", strPtr("064/123-4567"), "rs", true, "+381641234567"},
+		{"UK national with spaces", strPtr("020 7946 0958"), "GB", true, "+442079460958"},
+		{"Italian number keeps leading zero", strPtr("06 1234 5678"), "IT", true, "+390612345678"},
+		{"International overrides country", strPtr("+44 20 7946 0958"), "US", true, "+442079460958"},
+		{"Unknown calling code in E.164", strPtr("+8612345678901"), "", true, "+8612345678901"},
+		{"Nil phone", nil, "US", false, ""},
+		{"National without country", strPtr("0641234567"), "", false, ""},
+		{"National with unknown country", strPtr("0641234567"), "ZZ", false, ""},
+		{"Too short for US plan", strPtr("+1 555 123"), "", false, ""},
+		{"Too long for Serbian plan", strPtr("+381 64 1234 56789"), "", false, ""},
+		{"Too short for Serbian plan", strPtr("064 12345"), "RS", false, ""},
+		{"Too long for E.164", strPtr("+8612345678901234"), "", false, ""},
+		{"Letters in number", strPtr("555-CALL-NOW"), "US", false, ""},
+		{"Invalid characters", strPtr("+1234abc8901"), "", false, ""},
+		{"Plus sign in the middle", strPtr("064+1234567"), "RS", false, ""},
+		{"Only formatting", strPtr("( ) -"), "US", false, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var inputCopy *string
+			if tt.input != nil {
+				val := *tt.input
+				inputCopy = &val
+			}
+			got := NormalizePhoneNumber(inputCopy, tt.country)
+
+			if got != tt.want {
+				t.Errorf("NormalizePhoneNumber(%v, %q) = %v, want %v", tt.input, tt.country, got, tt.want)
+			}
+
+			if got && inputCopy != nil {
+				expected := tt.output
+				actual := *inputCopy
+				if actual != expected {
+					t.Errorf("NormalizePhoneNumber(%v, %q) normalized to %q, want %q", tt.input, tt.country, actual, expected)
+				}
+			}
+		})
+	}
+}
+
+// Checks if the longest matching calling code decides the length check, even when a shorter code
+// is a prefix of it.
+func TestValidatePhonePlan(t *testing.T) {
+	plans := plansByCallingCode(map[string]PhonePlan{
+		"US": {CallingCode: "1", MinLength: 10, MaxLength: 10},
+		"XX": {CallingCode: "124", MinLength: 7, MaxLength: 7},
+		"RS": {CallingCode: "381", MinLength: 8, MaxLength: 9},
+	})
+
+	original := callingCodePlans
+	callingCodePlans = plans
+	t.Cleanup(func() { callingCodePlans = original })
+
+	tests := []struct {
+		name   string
+		digits string
+		want   bool
+	}{
+		{"Shorter code", "12025550123", true},
+		{"Longer code wins", "1242555012", true},
+		{"Too long for the longer code", "12425550123", false},
+		{"Three-digit code", "381641234567", true},
+		{"Unknown code", "8612345678901", true},
+		{"Single digit", "1", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for range 20 {
+				if got := validatePhonePlan(tt.digits); got != tt.want {
+					t.Fatalf("validatePhonePlan(%q) = %v, want %v", tt.digits, got, tt.want)
+				}
+			}
+		})
+	}
+}
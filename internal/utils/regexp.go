@@ -4,10 +4,26 @@ import (
 	"regexp"
 )
 
+// Length limits of lead fields, matching the sizes of the "leads" table columns.
+const (
+	MinNameLength    = 2
+	MaxNameLength    = 31
+	MaxEmailLength   = 255
+	MinMessageLength = 2
+	MaxMessageLength = 255
+	MaxUTMLength     = 127
+	MaxURLLength     = 511
+	MaxUserAgent     = 255
+)
+
 var (
 	phoneRegExp = regexp.MustCompile(`^\+\d{10,15}$`)
 	emailRegExp = regexp.MustCompile(`^(?:[a-z0-9!#$%&'*+/=?^_` + "`" + `{|}~-]{2,}(?:\.[a-z0-9!#$%&'*+/=?^_` + "`" + `{|}~-]+)*|"(?:[\x01-\x08\x0b\x0c\x0e-\x1f\x21\x23-\x5b\x5d-\x7f]|\\[\x01-\x09\x0b\x0c\x0e-\x7f])*")@(?:(?:[a-z0-9](?:[a-z0-9-]*[a-z0-9])?\.)+[a-z0-9](?:[a-z0-9-]*[a-z0-9])?|\[(?:(?:25[0-5]|2[0-4][0-9]|[01]?[0-9][0-9]?)\.){3}(?:25[0-5]|2[0-4][0-9]|[01]?[0-9][0-9]?|[a-z0-9-]*[a-z0-9]:(?:[\x01-\x08\x0b\x0c\x0e-\x1f\x21-\x5a\x53-\x7f]|\\[\x01-\x09\x0b\x0c\x0e-\x7f])+)\])$`)
 	nameRegExp  = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9\s\-',.]{0,98}[A-Za-z0-9.]$`)
+
+	shortCodeRegExp    = regexp.MustCompile(`^\d{5,6}$`)
+	alphanumericRegExp = regexp.MustCompile(`^[A-Za-z0-9 ]{1,11}$`)
+	letterRegExp       = regexp.MustCompile(`[A-Za-z]`)
 )
 
 // Checks if the input string matches the expected phone number format.
@@ -22,6 +38,14 @@ func ValidateEmail(value string) bool {
 	return emailRegExp.MatchString(value)
 }
 
+// Checks if the input is a sender accepted by Azure Communication Services SMS: an E.164 phone number,
+// a short code (5 or 6 digits) or an alphanumeric sender ID (up to 11 letters, digits and spaces, with a letter).
+func ValidateSMSSender(value string) bool {
+	return phoneRegExp.MatchString(value) ||
+		shortCodeRegExp.MatchString(value) ||
+		(alphanumericRegExp.MatchString(value) && letterRegExp.MatchString(value))
+}
+
 // Checks if the input name is valid and normalizes it for consistency.
 // Used to ensure names in requests are valid and human friendly before further processing.
 func ValidateAndNormalizeName(name *string) bool {
@@ -40,7 +64,7 @@ func ValidateAndNormalizeName(name *string) bool {
 		return false
 	}
 
-	if len(normalized) <= 2 || len(normalized) > 32 {
+	if len(normalized) < MinNameLength || len(normalized) > MaxNameLength {
 		return false
 	}
 
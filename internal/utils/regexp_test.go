@@ -50,6 +50,29 @@ func TestValidateEmail(t *testing.T) {
 	}
 }
 
+// Checks if every SMS sender format accepted by Azure is recognized.
+func TestValidateSMSSender(t *testing.T) {
+	tests := []regexpTestCase{
+		{"Phone number", "+18005550100", true},
+		{"Short code", "12345", true},
+		{"Six digit short code", "123456", true},
+		{"Alphanumeric sender ID", "Acme", true},
+		{"Alphanumeric sender ID with digits and space", "Acme Leads1", true},
+		{"Alphanumeric sender ID too long", "Acme Leads Inc", false},
+		{"Digits only", "1234567", false},
+		{"Invalid characters", "Acme-Leads", false},
+		{"Empty", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ValidateSMSSender(tt.input); got != tt.want {
+				t.Errorf("ValidateSMSSender(%q) = %v, want %v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
 // Convert a string to a pointer
 func strPtr(s string) *string {
 	return &s
@@ -75,6 +98,9 @@ func TestValidateAndNormalizeName(t *testing.T) {
 		{"Name with numbers", strPtr("John123"), true, "John123"},
 		{"Name with period at end", strPtr("John."), true, "John."},
 		{"Name with multiple spaces and punctuation", strPtr("  John   ,   Doe  "), true, "John , Doe"},
+		{"Name with two letters", strPtr("Al"), true, "Al"},
+		{"Name at column limit", strPtr("Johnathan Alexander Doe-Smithso"), true, "Johnathan Alexander Doe-Smithso"},
+		{"Name over column limit", strPtr("Johnathan Alexander Doe-Smithson"), false, ""},
 		{"Nil name", nil, false, ""},
 		{"Name with extra spaces and symbols", strPtr("   _   John  _  Doe   _   "), false, ""},
 		{"Name with invalid characters", strPtr("John@Doe"), false, ""},
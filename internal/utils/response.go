@@ -1,6 +1,10 @@
 package utils
 
-import "github.com/gin-gonic/gin"
+import (
+	"github.com/gin-gonic/gin"
+
+	"communications/internal/logger"
+)
 
 type Status string
 
@@ -14,9 +18,10 @@ type DefaultResponse map[string]string
 
 // Contains metadata for every API response, such as status, message, and timestamp.
 type Meta struct {
-	Status    Status `json:"status"`    // "success" or "error"
-	Message   string `json:"message"`   // Human-readable message for the response.
-	Timestamp string `json:"timestamp"` // ISO timestamp of the response.
+	Status    Status `json:"status"`               // "success" or "error"
+	Message   string `json:"message"`              // Human-readable message for the response.
+	Timestamp string `json:"timestamp"`            // ISO timestamp of the response.
+	RequestID string `json:"request_id,omitempty"` // ID of the request, also returned in the X-Request-ID header.
 }
 
 // Wraps the response body and metadata for all API responses.
@@ -34,7 +39,13 @@ func Reject(c *gin.Context, code int, message string) {
 			Status:    StatusError,
 			Message:   message,
 			Timestamp: GetCurrentTimestamp(),
+			RequestID: GetRequestID(c),
 		},
 		Data: DefaultResponse{},
 	})
 }
+
+// Returns the ID of the current request, used to correlate responses with log lines.
+func GetRequestID(c *gin.Context) string {
+	return logger.RequestID(c.Request.Context())
+}
@@ -1,9 +1,14 @@
 package utils
 
 import (
+	"fmt"
+	"net"
+	"net/url"
+	"reflect"
 	"strconv"
 	"strings"
 	"time"
+	"unicode"
 )
 
 // Generic interface for all common numeric types.
@@ -27,19 +32,98 @@ func SplitString(value, separator string) []string {
 	return strings.Split(value, separator)
 }
 
-// Converts a string to a specified numeric type (int, float, etc.).
-// Useful for converting database string results to Go numeric types in a type-safe way.
-func StringToNumber[T Number](value string) T {
-	if integer, err := strconv.Atoi(value); err == nil {
-		return any(integer).(T)
+// Removes control characters (except newlines and tabs) and trims surrounding whitespace.
+// Used to clean free-text user input before it is stored or forwarded in notifications.
+func SanitizeText(value string) string {
+	value = strings.ReplaceAll(value, "\r\n", "\n")
+
+	sanitized := strings.Map(func(r rune) rune {
+		if r == '\n' || r == '\t' {
+			return r
+		}
+		if unicode.IsControl(r) || r == unicode.ReplacementChar {
+			return -1
+		}
+		return r
+	}, value)
+
+	return strings.TrimSpace(sanitized)
+}
+
+// Sanitizes the input as a single line: control characters are removed, whitespace is collapsed
+// and the result is truncated to at most max characters.
+// Used for short metadata fields (e.g. UTM parameters, user agent) that must never break the layout or column size.
+func SanitizeLine(value string, max int) string {
+	value = strings.Join(strings.Fields(SanitizeText(value)), " ")
+
+	if runes := []rune(value); len(runes) > max {
+		value = strings.TrimSpace(string(runes[:max]))
+	}
+
+	return value
+}
+
+// Sanitizes the input as an absolute http(s) URL of at most max characters once re-encoded (e.g. spaces as %20).
+// Returns an empty string for anything else, so untrusted values are dropped instead of stored.
+func SanitizeURL(value string, max int) string {
+	value = strings.TrimSpace(SanitizeText(value))
+	if value == "" {
+		return ""
+	}
+
+	parsed, err := url.Parse(value)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return ""
 	}
 
-	if decimal, err := strconv.ParseFloat(value, 64); err == nil {
-		return any(decimal).(T)
+	if value = parsed.String(); len(value) > max {
+		return ""
 	}
 
-	var zero T
-	return zero
+	return value
+}
+
+// Truncates an IP address for privacy by zeroing the host part (IPv4 to /24, IPv6 to /48).
+// Returns an empty string if the input is not a valid IP address.
+func AnonymizeIP(value string) string {
+	ip := net.ParseIP(value)
+	if ip == nil {
+		return ""
+	}
+
+	if ipv4 := ip.To4(); ipv4 != nil {
+		return ipv4.Mask(net.CIDRMask(24, 32)).String()
+	}
+
+	return ip.Mask(net.CIDRMask(48, 128)).String()
+}
+
+// Converts a string to a specified numeric type (int, float, etc.) using the bit size of that type.
+// Returns an error instead of a silent zero for values that do not fit (e.g. "abc", "1.5" as int or "300" as uint8).
+func StringToNumber[T Number](value string) (T, error) {
+	value = strings.TrimSpace(value)
+	kind := reflect.TypeFor[T]()
+
+	switch kind.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		integer, err := strconv.ParseInt(value, 10, kind.Bits())
+		if err != nil {
+			return 0, fmt.Errorf("%q is not a valid %s", value, kind)
+		}
+		return T(integer), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		integer, err := strconv.ParseUint(value, 10, kind.Bits())
+		if err != nil {
+			return 0, fmt.Errorf("%q is not a valid %s", value, kind)
+		}
+		return T(integer), nil
+	default:
+		decimal, err := strconv.ParseFloat(value, kind.Bits())
+		if err != nil {
+			return 0, fmt.Errorf("%q is not a valid %s", value, kind)
+		}
+		return T(decimal), nil
+	}
 }
 
 // Returns the current UTC time in RFC3339 format.
@@ -0,0 +1,155 @@
+package utils
+
+import (
+	"testing"
+)
+
+// Checks if control characters and surrounding whitespace are removed from the input text.
+func TestSanitizeText(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  string
+		output string
+	}{
+		{"Plain text", "Hello there", "Hello there"},
+		{"Surrounding whitespace", "  \t Hello there \n ", "Hello there"},
+		{"Keeps inner newlines and tabs", "Line one\n\tLine two", "Line one\n\tLine two"},
+		{"Normalizes CRLF", "Line one\r\nLine two", "Line one\nLine two"},
+		{"Strips NUL and bell", "Hel\x00lo\x07", "Hello"},
+		{"Strips escape sequences", "\x1b[31mRed\x1b[0m", "[31mRed[0m"},
+		{"Strips lone carriage return", "Hello\rthere", "Hellothere"},
+		{"Strips DEL and C1 controls", "Hello\x7f\u0085there", "Hellothere"},
+		{"Keeps unicode letters", "Zdravo, Đorđe!", "Zdravo, Đorđe!"},
+		{"Only control characters", "\x00\x01\x02", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SanitizeText(tt.input); got != tt.output {
+				t.Errorf("SanitizeText(%q) = %q, want %q", tt.input, got, tt.output)
+			}
+		})
+	}
+}
+
+// Checks if the input is collapsed to a single line and truncated to the maximum length.
+func TestSanitizeLine(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  string
+		max    int
+		output string
+	}{
+		{"Plain value", "spring_sale", 127, "spring_sale"},
+		{"Collapses whitespace and newlines", "  spring\n\tsale   2025 ", 127, "spring sale 2025"},
+		{"Strips control characters", "news\x00letter", 127, "newsletter"},
+		{"Truncates to max characters", "abcdefghij", 5, "abcde"},
+		{"Truncates unicode by characters", "ĐĐĐĐĐĐ", 3, "ĐĐĐ"},
+		{"Trims space left by truncation", "abcd efgh", 5, "abcd"},
+		{"Only whitespace", " \n\t ", 127, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SanitizeLine(tt.input, tt.max); got != tt.output {
+				t.Errorf("SanitizeLine(%q, %d) = %q, want %q", tt.input, tt.max, got, tt.output)
+			}
+		})
+	}
+}
+
+// Checks if only absolute http(s) URLs within the maximum length are kept.
+func TestSanitizeURL(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  string
+		max    int
+		output string
+	}{
+		{"Valid https URL", "https://example.com/landing?utm_source=google", 511, "https://example.com/landing?utm_source=google"},
+		{"Valid http URL with whitespace", "  http://example.com/  ", 511, "http://example.com/"},
+		{"JavaScript scheme", "javascript:alert(1)", 511, ""},
+		{"Relative URL", "/landing", 511, ""},
+		{"Missing host", "https://", 511, ""},
+		{"Too long", "https://example.com/abcdefghij", 20, ""},
+		{"Too long once encoded", "https://example.com/a b c", 27, ""},
+		{"Encoded within the limit", "https://example.com/a b c", 29, "https://example.com/a%20b%20c"},
+		{"Empty", "", 511, ""},
+		{"Invalid URL", "https://exa mple.com/%zz", 511, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SanitizeURL(tt.input, tt.max); got != tt.output {
+				t.Errorf("SanitizeURL(%q, %d) = %q, want %q", tt.input, tt.max, got, tt.output)
+			}
+		})
+	}
+}
+
+// Checks if IP addresses are truncated to their network prefix.
+func TestAnonymizeIP(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  string
+		output string
+	}{
+		{"IPv4", "203.0.113.57", "203.0.113.0"},
+		{"IPv4-mapped IPv6", "::ffff:203.0.113.57", "203.0.113.0"},
+		{"IPv6", "2001:db8:85a3:8d3:1319:8a2e:370:7348", "2001:db8:85a3::"},
+		{"IPv6 loopback", "::1", "::"},
+		{"Invalid IP", "not-an-ip", ""},
+		{"Empty", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := AnonymizeIP(tt.input); got != tt.output {
+				t.Errorf("AnonymizeIP(%q) = %q, want %q", tt.input, got, tt.output)
+			}
+		})
+	}
+}
+
+// Checks if the input string is parsed into the requested numeric type, rejecting garbage and overflows.
+func TestStringToNumber(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  bool
+		value float64
+		parse func(string) (float64, error)
+	}{
+		{"Int", "60", true, 60, parseAs[int]},
+		{"Negative int", "-5", true, -5, parseAs[int]},
+		{"Surrounding whitespace", " 10 ", true, 10, parseAs[int]},
+		{"Letters as int", "abc", false, 0, parseAs[int]},
+		{"Empty as int", "", false, 0, parseAs[int]},
+		{"Decimal as int", "1.5", false, 0, parseAs[int]},
+		{"Uint8 in range", "255", true, 255, parseAs[uint8]},
+		{"Uint8 overflow", "300", false, 0, parseAs[uint8]},
+		{"Negative uint", "-1", false, 0, parseAs[uint]},
+		{"Float64", "1.5", true, 1.5, parseAs[float64]},
+		{"Int as float32", "2", true, 2, parseAs[float32]},
+		{"Letters as float", "1.5x", false, 0, parseAs[float64]},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			value, err := tt.parse(tt.input)
+
+			if got := err == nil; got != tt.want {
+				t.Fatalf("StringToNumber(%q) error = %v, want success %v", tt.input, err, tt.want)
+			}
+			if value != tt.value {
+				t.Errorf("StringToNumber(%q) = %v, want %v", tt.input, value, tt.value)
+			}
+		})
+	}
+}
+
+// Parses the input as T and widens the result, so one table can cover several numeric types.
+func parseAs[T Number](value string) (float64, error) {
+	number, err := StringToNumber[T](value)
+	return float64(number), err
+}
@@ -0,0 +1,8 @@
+ALTER TABLE "leads"
+ALTER COLUMN "phone" TYPE VARCHAR(15);
+
+ALTER TABLE "clients"
+ALTER COLUMN "phone" TYPE VARCHAR(15);
+
+ALTER TABLE "clients"
+DROP COLUMN "country";
\ No newline at end of file
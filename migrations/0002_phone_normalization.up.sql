@@ -0,0 +1,8 @@
+ALTER TABLE "clients"
+ADD COLUMN "country" VARCHAR(2);
+
+ALTER TABLE "clients"
+ALTER COLUMN "phone" TYPE VARCHAR(16);
+
+ALTER TABLE "leads"
+ALTER COLUMN "phone" TYPE VARCHAR(16);
@@ -0,0 +1,2 @@
+ALTER TABLE "leads"
+DROP COLUMN "email_verdict";
\ No newline at end of file
@@ -0,0 +1,2 @@
+ALTER TABLE "leads"
+ADD COLUMN "email_verdict" VARCHAR(15);
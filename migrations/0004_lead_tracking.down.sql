@@ -0,0 +1,9 @@
+ALTER TABLE "leads"
+DROP COLUMN "user_agent",
+DROP COLUMN "landing_page",
+DROP COLUMN "referrer",
+DROP COLUMN "utm_content",
+DROP COLUMN "utm_term",
+DROP COLUMN "utm_campaign",
+DROP COLUMN "utm_medium",
+DROP COLUMN "utm_source";
\ No newline at end of file
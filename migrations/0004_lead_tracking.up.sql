@@ -0,0 +1,9 @@
+ALTER TABLE "leads"
+ADD COLUMN "utm_source" VARCHAR(127),
+ADD COLUMN "utm_medium" VARCHAR(127),
+ADD COLUMN "utm_campaign" VARCHAR(127),
+ADD COLUMN "utm_term" VARCHAR(127),
+ADD COLUMN "utm_content" VARCHAR(127),
+ADD COLUMN "referrer" VARCHAR(511),
+ADD COLUMN "landing_page" VARCHAR(511),
+ADD COLUMN "user_agent" VARCHAR(255);
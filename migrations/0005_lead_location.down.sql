@@ -0,0 +1,4 @@
+ALTER TABLE "leads"
+DROP COLUMN "city",
+DROP COLUMN "country",
+DROP COLUMN "ip_address";
\ No newline at end of file
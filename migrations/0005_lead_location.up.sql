@@ -0,0 +1,4 @@
+ALTER TABLE "leads"
+ADD COLUMN "ip_address" VARCHAR(45),
+ADD COLUMN "country" VARCHAR(2),
+ADD COLUMN "city" VARCHAR(127);
@@ -0,0 +1,3 @@
+DROP INDEX "public"."IDX_RateLimit_expires_at";
+
+DROP TABLE "rate_limits";
\ No newline at end of file
@@ -0,0 +1,10 @@
+CREATE UNLOGGED TABLE
+  "rate_limits" (
+    "key" VARCHAR(255) NOT NULL,
+    "tokens" DOUBLE PRECISION NOT NULL,
+    "updated_at" TIMESTAMP NOT NULL DEFAULT now (),
+    "expires_at" TIMESTAMP NOT NULL DEFAULT now (),
+    CONSTRAINT "PK_RateLimit" PRIMARY KEY ("key")
+  );
+
+CREATE INDEX "IDX_RateLimit_expires_at" ON "rate_limits" ("expires_at");
@@ -0,0 +1,2 @@
+ALTER TABLE "rate_limits"
+ALTER COLUMN "key" TYPE VARCHAR(255);
\ No newline at end of file
@@ -0,0 +1,7 @@
+ALTER TABLE "client_usage" DROP CONSTRAINT "FK_ClientUsage_Client";
+
+DROP TABLE "client_usage";
+
+ALTER TABLE "clients"
+DROP COLUMN "sms_quota",
+DROP COLUMN "email_quota";
\ No newline at end of file
@@ -0,0 +1,16 @@
+ALTER TABLE "clients"
+ADD COLUMN "email_quota" INTEGER,
+ADD COLUMN "sms_quota" INTEGER;
+
+CREATE TABLE
+  "client_usage" (
+    "client_id" uuid NOT NULL,
+    "period" DATE NOT NULL,
+    "emails" INTEGER NOT NULL DEFAULT 0,
+    "sms" INTEGER NOT NULL DEFAULT 0,
+    "updated_at" TIMESTAMP NOT NULL DEFAULT now (),
+    CONSTRAINT "PK_ClientUsage" PRIMARY KEY ("client_id", "period")
+  );
+
+ALTER TABLE "client_usage"
+ADD CONSTRAINT "FK_ClientUsage_Client" FOREIGN KEY ("client_id") REFERENCES "clients" ("id") ON DELETE CASCADE ON UPDATE CASCADE;
@@ -0,0 +1,7 @@
+ALTER TABLE "leads"
+ALTER COLUMN "phone" DROP NOT NULL,
+ALTER COLUMN "email" DROP NOT NULL,
+ALTER COLUMN "name" DROP NOT NULL;
+
+ALTER TABLE "clients"
+DROP COLUMN "verified";
\ No newline at end of file
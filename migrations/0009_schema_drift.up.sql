@@ -0,0 +1,13 @@
+ALTER TABLE "clients"
+ADD COLUMN "verified" BOOLEAN NOT NULL DEFAULT false;
+
+UPDATE "leads" SET "name" = '' WHERE "name" IS NULL;
+
+UPDATE "leads" SET "email" = '' WHERE "email" IS NULL;
+
+UPDATE "leads" SET "phone" = '' WHERE "phone" IS NULL;
+
+ALTER TABLE "leads"
+ALTER COLUMN "name" SET NOT NULL,
+ALTER COLUMN "email" SET NOT NULL,
+ALTER COLUMN "phone" SET NOT NULL;
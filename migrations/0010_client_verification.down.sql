@@ -0,0 +1,6 @@
+ALTER TABLE "clients"
+DROP COLUMN "email_verified",
+DROP COLUMN "phone_verified",
+DROP COLUMN "phone_code",
+DROP COLUMN "phone_code_expires_at",
+DROP COLUMN "phone_code_attempts";
@@ -0,0 +1,9 @@
+ALTER TABLE "clients"
+ADD COLUMN "email_verified" BOOLEAN NOT NULL DEFAULT false,
+ADD COLUMN "phone_verified" BOOLEAN NOT NULL DEFAULT false,
+ADD COLUMN "phone_code" VARCHAR(64),
+ADD COLUMN "phone_code_expires_at" TIMESTAMP,
+ADD COLUMN "phone_code_attempts" INTEGER NOT NULL DEFAULT 0;
+
+-- Clients added before verification existed were onboarded manually, so they keep receiving leads.
+UPDATE "clients" SET "email_verified" = true, "phone_verified" = true, "verified" = true;
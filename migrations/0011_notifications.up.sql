@@ -0,0 +1,25 @@
+CREATE TABLE
+  "notifications" (
+    "id" SERIAL NOT NULL,
+    "client_id" uuid NOT NULL,
+    "lead_id" INTEGER,
+    "channel" VARCHAR(5) NOT NULL,
+    "message_id" VARCHAR(127) NOT NULL,
+    "recipient" VARCHAR(255) NOT NULL,
+    "status" VARCHAR(15) NOT NULL DEFAULT 'accepted',
+    "status_reason" VARCHAR(255),
+    "created_at" TIMESTAMP NOT NULL DEFAULT now (),
+    "updated_at" TIMESTAMP NOT NULL DEFAULT now (),
+    CONSTRAINT "PK_Notification" PRIMARY KEY ("id")
+  );
+
+ALTER TABLE "notifications"
+ADD CONSTRAINT "UQ_Notification_channel_message_id" UNIQUE ("channel", "message_id");
+
+ALTER TABLE "notifications"
+ADD CONSTRAINT "FK_Notification_Client" FOREIGN KEY ("client_id") REFERENCES "clients" ("id") ON DELETE CASCADE ON UPDATE CASCADE;
+
+ALTER TABLE "notifications"
+ADD CONSTRAINT "FK_Notification_Lead" FOREIGN KEY ("lead_id") REFERENCES "leads" ("id") ON DELETE SET NULL ON UPDATE CASCADE;
+
+CREATE INDEX "IDX_Notification_lead_id" ON "notifications" ("lead_id");
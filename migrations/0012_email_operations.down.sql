@@ -0,0 +1,4 @@
+DROP INDEX "IDX_Notification_pending_operations";
+
+ALTER TABLE "notifications"
+DROP COLUMN "operation_status";
@@ -0,0 +1,5 @@
+ALTER TABLE "notifications"
+ADD COLUMN "operation_status" VARCHAR(15);
+
+CREATE INDEX "IDX_Notification_pending_operations" ON "notifications" ("created_at")
+WHERE "channel" = 'email' AND "operation_status" IS NULL;
@@ -0,0 +1,13 @@
+CREATE TABLE
+  "suppressions" (
+    "id" SERIAL NOT NULL,
+    "channel" VARCHAR(5) NOT NULL,
+    "recipient" VARCHAR(255) NOT NULL,
+    "reason" VARCHAR(255),
+    "source" VARCHAR(15) NOT NULL,
+    "created_at" TIMESTAMP NOT NULL DEFAULT now (),
+    CONSTRAINT "PK_Suppression" PRIMARY KEY ("id")
+  );
+
+ALTER TABLE "suppressions"
+ADD CONSTRAINT "UQ_Suppression_channel_recipient" UNIQUE ("channel", "recipient");
@@ -0,0 +1,3 @@
+DROP INDEX "IDX_Notification_recipient";
+
+DROP TABLE "replies";
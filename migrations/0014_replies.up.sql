@@ -0,0 +1,26 @@
+CREATE TABLE
+  "replies" (
+    "id" SERIAL NOT NULL,
+    "client_id" uuid NOT NULL,
+    "lead_id" INTEGER NOT NULL,
+    "message_id" VARCHAR(127) NOT NULL,
+    "sender" VARCHAR(31) NOT NULL,
+    "message" TEXT NOT NULL,
+    "forwarded_channel" VARCHAR(5),
+    "forwarded_message_id" VARCHAR(127),
+    "received_at" TIMESTAMP NOT NULL DEFAULT now (),
+    CONSTRAINT "PK_Reply" PRIMARY KEY ("id")
+  );
+
+ALTER TABLE "replies"
+ADD CONSTRAINT "UQ_Reply_message_id" UNIQUE ("message_id");
+
+ALTER TABLE "replies"
+ADD CONSTRAINT "FK_Reply_Client" FOREIGN KEY ("client_id") REFERENCES "clients" ("id") ON DELETE CASCADE ON UPDATE CASCADE;
+
+ALTER TABLE "replies"
+ADD CONSTRAINT "FK_Reply_Lead" FOREIGN KEY ("lead_id") REFERENCES "leads" ("id") ON DELETE CASCADE ON UPDATE CASCADE;
+
+CREATE INDEX "IDX_Reply_lead_id" ON "replies" ("lead_id");
+
+CREATE INDEX "IDX_Notification_recipient" ON "notifications" ("channel", "recipient", "created_at");
@@ -0,0 +1 @@
+-- Nothing to undo: the up migration only checks the leads.
@@ -0,0 +1,11 @@
+DO $$
+DECLARE
+  incomplete INTEGER;
+BEGIN
+  SELECT count(*) INTO incomplete FROM "leads" WHERE "name" = '' OR "email" = '' OR "phone" = '';
+
+  IF incomplete > 0 THEN
+    RAISE EXCEPTION '% leads have no name, email or phone; complete or delete them before migrating', incomplete
+      USING HINT = 'SELECT * FROM "leads" WHERE "name" = '''' OR "email" = '''' OR "phone" = ''''';
+  END IF;
+END $$;
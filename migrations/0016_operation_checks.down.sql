@@ -0,0 +1,7 @@
+DROP INDEX "IDX_Notification_pending_operations";
+
+CREATE INDEX "IDX_Notification_pending_operations" ON "notifications" ("created_at")
+WHERE "channel" = 'email' AND "operation_status" IS NULL;
+
+ALTER TABLE "notifications"
+DROP COLUMN "checked_at";
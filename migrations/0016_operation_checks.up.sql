@@ -0,0 +1,7 @@
+ALTER TABLE "notifications"
+ADD COLUMN "checked_at" TIMESTAMP;
+
+DROP INDEX "IDX_Notification_pending_operations";
+
+CREATE INDEX "IDX_Notification_pending_operations" ON "notifications" ("checked_at" NULLS FIRST, "created_at")
+WHERE "channel" = 'email' AND "operation_status" IS NULL;
@@ -0,0 +1,9 @@
+DROP INDEX "IDX_Notification_pending_operations";
+
+CREATE INDEX "IDX_Notification_pending_operations" ON "notifications" ("checked_at" NULLS FIRST, "created_at")
+WHERE "channel" = 'email' AND "operation_status" IS NULL;
+
+DELETE FROM "notifications" WHERE "message_id" IS NULL;
+
+ALTER TABLE "notifications"
+ALTER COLUMN "message_id" SET NOT NULL;
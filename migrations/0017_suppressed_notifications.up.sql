@@ -0,0 +1,7 @@
+ALTER TABLE "notifications"
+ALTER COLUMN "message_id" DROP NOT NULL;
+
+DROP INDEX "IDX_Notification_pending_operations";
+
+CREATE INDEX "IDX_Notification_pending_operations" ON "notifications" ("checked_at" NULLS FIRST, "created_at")
+WHERE "channel" = 'email' AND "message_id" IS NOT NULL AND "operation_status" IS NULL;
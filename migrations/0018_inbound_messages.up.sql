@@ -0,0 +1,6 @@
+CREATE TABLE
+  "inbound_messages" (
+    "message_id" VARCHAR(127) NOT NULL,
+    "received_at" TIMESTAMP NOT NULL DEFAULT now (),
+    CONSTRAINT "PK_InboundMessage" PRIMARY KEY ("message_id")
+  );
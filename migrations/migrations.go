@@ -0,0 +1,10 @@
+// Package migrations embeds the SQL migrations into the binary.
+// Files are named <version>_<title>.(up|down).sql and applied in version order by golang-migrate.
+package migrations
+
+import "embed"
+
+// SQL migration files, so the binary can migrate from any working directory.
+//
+//go:embed *.sql
+var FS embed.FS